@@ -1,5 +1,10 @@
 package protocol
 
+import (
+	"encoding/json"
+	"errors"
+)
+
 // LSPAny can be a primitive (string, number, boolean, null), an object, or an array.
 //
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#lspAny
@@ -195,3 +200,42 @@ const (
 	// Markdown is supported as a content format
 	MarkupKindMarkdown MarkupKind = "markdown"
 )
+
+// StringOrMarkupContent is a `string | MarkupContent` value, as used for
+// documentation and tooltip properties.
+type StringOrMarkupContent struct {
+	String        *string
+	MarkupContent *MarkupContent
+}
+
+func (s StringOrMarkupContent) MarshalJSON() ([]byte, error) {
+	if s.String != nil {
+		return json.Marshal(*s.String)
+	}
+	if s.MarkupContent != nil {
+		return json.Marshal(s.MarkupContent)
+	}
+	return []byte("null"), nil
+}
+
+func (s *StringOrMarkupContent) UnmarshalJSON(data []byte) error {
+	*s = StringOrMarkupContent{}
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		s.String = &str
+		return nil
+	}
+
+	var markup MarkupContent
+	if err := json.Unmarshal(data, &markup); err == nil && markup.Kind != "" {
+		s.MarkupContent = &markup
+		return nil
+	}
+
+	return errors.New("invalid StringOrMarkupContent: not string, MarkupContent, or null")
+}
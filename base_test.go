@@ -95,3 +95,32 @@ func Test_Base_ChangeAnnotationJSONRoundTrip(t *testing.T) {
 		t.Fatalf("expected needsConfirmation=%v, got %+v", *original.NeedsConfirmation, decoded.NeedsConfirmation)
 	}
 }
+
+func Test_Base_StringOrMarkupContentRoundTrip(t *testing.T) {
+	for _, input := range []string{`"Get the route URL."`, `{"kind":"markdown","value":"**route**"}`} {
+		var doc protocol.StringOrMarkupContent
+		if err := json.Unmarshal([]byte(input), &doc); err != nil {
+			t.Fatalf("unmarshal %s failed: %v", input, err)
+		}
+
+		data, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+		if string(data) != input {
+			t.Fatalf("expected %s, got %s", input, data)
+		}
+	}
+
+	var doc protocol.StringOrMarkupContent
+	if err := json.Unmarshal([]byte(`42`), &doc); err == nil {
+		t.Fatalf("expected error for number documentation")
+	}
+
+	if err := json.Unmarshal([]byte(`null`), &doc); err != nil || doc.String != nil || doc.MarkupContent != nil {
+		t.Fatalf("expected null to decode to an empty value, got %+v, %v", doc, err)
+	}
+	if data, err := json.Marshal(doc); err != nil || string(data) != "null" {
+		t.Fatalf("expected empty value to marshal to null, got %s, %v", data, err)
+	}
+}
@@ -0,0 +1,151 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// CancelRegistry is a Handler that gives every request a cancellable context
+// and cancels it when a matching `$/cancelRequest` notification arrives.
+//
+// A request whose handler fails with context.Canceled is answered with
+// RPCRequestCancelled. Handlers that finish anyway may still return their
+// result, as allowed by the specification.
+//
+// `$/cancelRequest` notifications are passed on to the next handler as well;
+// it does not need to handle them.
+//
+// A cancellation may be handled before the request it cancels, for example
+// when the transport dispatches requests concurrently. The ids of the last
+// MaxEarlyCancellations such cancellations are remembered, and a request with
+// one of those ids is answered with RPCRequestCancelled without being
+// handled.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#cancelRequest
+type CancelRegistry struct {
+	next Handler
+
+	mu         sync.Mutex
+	pending    map[RequestID]context.CancelFunc
+	early      map[RequestID]struct{}
+	earlyOrder []RequestID
+}
+
+// MaxEarlyCancellations is the number of cancellations for requests not seen
+// yet that a CancelRegistry remembers.
+const MaxEarlyCancellations = 128
+
+// NewCancelRegistry creates a cancel registry in front of next.
+func NewCancelRegistry(next Handler) *CancelRegistry {
+	return &CancelRegistry{
+		next:    next,
+		pending: make(map[RequestID]context.CancelFunc),
+		early:   make(map[RequestID]struct{}),
+	}
+}
+
+// Cancel cancels the context of the in-flight request with the given id. It
+// reports whether such a request was found. Otherwise the id is remembered,
+// so that a request with that id arriving later is cancelled right away.
+func (c *CancelRegistry) Cancel(id RequestID) bool {
+	c.mu.Lock()
+	cancel, ok := c.pending[id]
+	if !ok {
+		c.rememberEarly(id)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// rememberEarly records the cancellation of a request not seen yet, dropping
+// the oldest ones beyond MaxEarlyCancellations. The caller must hold c.mu.
+func (c *CancelRegistry) rememberEarly(id RequestID) {
+	if _, ok := c.early[id]; ok {
+		return
+	}
+	c.early[id] = struct{}{}
+	c.earlyOrder = append(c.earlyOrder, id)
+
+	// Ids consumed by their request are only removed from the map; skip them.
+	for len(c.early) > MaxEarlyCancellations {
+		oldest := c.earlyOrder[0]
+		c.earlyOrder = c.earlyOrder[1:]
+		delete(c.early, oldest)
+	}
+	if len(c.earlyOrder) > 2*MaxEarlyCancellations {
+		order := make([]RequestID, 0, len(c.early))
+		for _, id := range c.earlyOrder {
+			if _, ok := c.early[id]; ok {
+				order = append(order, id)
+			}
+		}
+		c.earlyOrder = order
+	}
+}
+
+// InFlight returns the number of requests currently being handled.
+func (c *CancelRegistry) InFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.pending)
+}
+
+func (c *CancelRegistry) Handle(ctx context.Context, req *Request) (any, error) {
+	if req.IsNotification() {
+		if req.Method == MethodCancelRequest {
+			return c.cancelRequest(ctx, req)
+		}
+		return c.next.Handle(ctx, req)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	id := *req.ID
+
+	c.mu.Lock()
+	_, cancelled := c.early[id]
+	if cancelled {
+		delete(c.early, id)
+	} else {
+		c.pending[id] = cancel
+	}
+	c.mu.Unlock()
+
+	if cancelled {
+		cancel()
+		return nil, NewResponseError(RPCRequestCancelled, "request %s cancelled", id)
+	}
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		cancel()
+	}()
+
+	result, err := c.next.Handle(ctx, req)
+	if err != nil && errors.Is(err, context.Canceled) {
+		return nil, NewResponseError(RPCRequestCancelled, "request %s cancelled", id)
+	}
+	return result, err
+}
+
+func (c *CancelRegistry) cancelRequest(ctx context.Context, req *Request) (any, error) {
+	var params CancelParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, NewResponseError(RPCInvalidParams, "invalid params: %v", err)
+	}
+	c.Cancel(params.Id)
+
+	result, err := c.next.Handle(ctx, req)
+	if isMethodNotFound(err) {
+		return nil, nil
+	}
+	return result, err
+}
@@ -0,0 +1,175 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func newBlockingRegistry(t *testing.T) (*protocol.CancelRegistry, <-chan struct{}, *int) {
+	t.Helper()
+
+	started := make(chan struct{}, 1)
+	cancelNotifications := 0
+	router := protocol.NewRouter()
+	router.Register("slow", protocol.HandlerFunc(func(ctx context.Context, req *protocol.Request) (any, error) {
+		started <- struct{}{}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return "done", nil
+		}
+	}))
+	router.HandleCancelRequest(func(ctx context.Context, params protocol.CancelParams) error {
+		cancelNotifications++
+		return nil
+	})
+	return protocol.NewCancelRegistry(router), started, &cancelNotifications
+}
+
+func Test_CancelRegistry_CancelsByID(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     protocol.RequestID
+		params string
+	}{
+		{name: "number", id: protocol.NumberRequestID(7), params: `{"id":7}`},
+		{name: "string", id: protocol.StringRequestID("7"), params: `{"id":"7"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, started, cancelNotifications := newBlockingRegistry(t)
+			id := tt.id
+
+			errs := make(chan error, 1)
+			go func() {
+				_, err := registry.Handle(context.Background(), &protocol.Request{ID: &id, Method: "slow"})
+				errs <- err
+			}()
+			<-started
+
+			if registry.InFlight() != 1 {
+				t.Fatalf("expected 1 in-flight request, got %d", registry.InFlight())
+			}
+
+			cancel := &protocol.Request{Method: protocol.MethodCancelRequest, Params: json.RawMessage(tt.params)}
+			if _, err := registry.Handle(context.Background(), cancel); err != nil {
+				t.Fatalf("cancel notification failed: %v", err)
+			}
+
+			select {
+			case err := <-errs:
+				expectErrorCode(t, err, protocol.RPCRequestCancelled)
+			case <-time.After(500 * time.Millisecond):
+				t.Fatalf("request was not cancelled")
+			}
+
+			if *cancelNotifications != 1 {
+				t.Fatalf("expected the cancel notification to reach the next handler")
+			}
+			if registry.InFlight() != 0 {
+				t.Fatalf("expected no in-flight requests, got %d", registry.InFlight())
+			}
+		})
+	}
+}
+
+func Test_CancelRegistry_IgnoresOtherIDs(t *testing.T) {
+	registry, started, _ := newBlockingRegistry(t)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := registry.Handle(context.Background(), newRequest(1, "slow", ``))
+		errs <- err
+	}()
+	<-started
+
+	if registry.Cancel(protocol.StringRequestID("1")) {
+		t.Fatalf("expected string id 1 not to match numeric id 1")
+	}
+	if !registry.Cancel(protocol.NumberRequestID(1)) {
+		t.Fatalf("expected numeric id 1 to be cancelled")
+	}
+
+	expectErrorCode(t, <-errs, protocol.RPCRequestCancelled)
+}
+
+func Test_CancelRegistry_PassesThroughWithoutCancelHandler(t *testing.T) {
+	router := protocol.NewRouter()
+	router.Register("fast", protocol.HandlerFunc(func(ctx context.Context, req *protocol.Request) (any, error) {
+		return "ok", nil
+	}))
+	registry := protocol.NewCancelRegistry(router)
+
+	result, err := registry.Handle(context.Background(), newRequest(1, "fast", ``))
+	if err != nil || result != "ok" {
+		t.Fatalf("unexpected result %v, %v", result, err)
+	}
+
+	if _, err := registry.Handle(context.Background(), newRequest(0, protocol.MethodCancelRequest, `{"id":1}`)); err != nil {
+		t.Fatalf("expected unhandled cancel notification to be ignored, got %v", err)
+	}
+
+	_, err = registry.Handle(context.Background(), newRequest(0, protocol.MethodCancelRequest, `{"id":true}`))
+	expectErrorCode(t, err, protocol.RPCInvalidParams)
+}
+
+func Test_CancelRegistry_CancelBeforeRequest(t *testing.T) {
+	registry, started, cancelNotifications := newBlockingRegistry(t)
+
+	cancel := &protocol.Request{Method: protocol.MethodCancelRequest, Params: json.RawMessage(`{"id":3}`)}
+	if _, err := registry.Handle(context.Background(), cancel); err != nil {
+		t.Fatalf("cancel notification failed: %v", err)
+	}
+	if *cancelNotifications != 1 {
+		t.Fatalf("expected the cancel notification to reach the next handler")
+	}
+
+	_, err := registry.Handle(context.Background(), newRequest(3, "slow", ``))
+	expectErrorCode(t, err, protocol.RPCRequestCancelled)
+
+	select {
+	case <-started:
+		t.Fatalf("expected the cancelled request not to be handled")
+	default:
+	}
+
+	// The cancellation is consumed by its request.
+	go func() {
+		_, _ = registry.Handle(context.Background(), newRequest(3, "slow", ``))
+	}()
+	select {
+	case <-started:
+		registry.Cancel(protocol.NumberRequestID(3))
+	case <-time.After(time.Second):
+		t.Fatalf("expected a later request with the same id to be handled")
+	}
+}
+
+func Test_CancelRegistry_BoundsEarlyCancellations(t *testing.T) {
+	registry, started, _ := newBlockingRegistry(t)
+
+	for i := int64(1); i <= protocol.MaxEarlyCancellations+1; i++ {
+		if registry.Cancel(protocol.NumberRequestID(i)) {
+			t.Fatalf("expected no in-flight request %d", i)
+		}
+	}
+
+	// The oldest cancellation was forgotten, the newest is still known.
+	_, err := registry.Handle(context.Background(), newRequest(protocol.MaxEarlyCancellations+1, "slow", ``))
+	expectErrorCode(t, err, protocol.RPCRequestCancelled)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := registry.Handle(context.Background(), newRequest(1, "slow", ``))
+		errs <- err
+	}()
+	<-started
+	registry.Cancel(protocol.NumberRequestID(1))
+	expectErrorCode(t, <-errs, protocol.RPCRequestCancelled)
+}
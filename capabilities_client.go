@@ -87,7 +87,6 @@ type (
 	CodeLensClientCapabilities                 = DynamicRegistrationClientCapabilities
 	DocumentColorClientCapabilities            = DynamicRegistrationClientCapabilities
 	DocumentFormattingClientCapabilities       = DynamicRegistrationClientCapabilities
-	DocumentRangeFormattingClientCapabilities  = DynamicRegistrationClientCapabilities
 	DocumentOnTypeFormattingClientCapabilities = DynamicRegistrationClientCapabilities
 	SelectionRangeClientCapabilities           = DynamicRegistrationClientCapabilities
 	CallHierarchyClientCapabilities            = DynamicRegistrationClientCapabilities
@@ -97,6 +96,18 @@ type (
 	InlineValueClientCapabilities              = DynamicRegistrationClientCapabilities
 )
 
+// DocumentRangeFormattingClientCapabilities - Client capabilities for range formatting.
+type DocumentRangeFormattingClientCapabilities struct {
+	// DynamicRegistration indicates whether range formatting supports dynamic
+	// registration.
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+	// RangesSupport indicates whether the client supports formatting multiple
+	// ranges at once.
+	//
+	// @since 3.18.0
+	RangesSupport bool `json:"rangesSupport,omitempty"`
+}
+
 // WorkspaceEditClientCapabilities - Client capabilities for workspace edits.
 type WorkspaceEditClientCapabilities struct {
 	// DocumentChanges indicates support for versioned document changes.
@@ -125,13 +136,13 @@ type WorkspaceEditClientCapabilitiesChangeAnnotationSupport struct {
 // WorkspaceSymbolClientCapabilitiesSymbolKind - Supported workspace symbol kinds.
 type WorkspaceSymbolClientCapabilitiesSymbolKind struct {
 	// ValueSet is the symbol kind values the client supports.
-	ValueSet []uint32 `json:"valueSet,omitempty"`
+	ValueSet []SymbolKind `json:"valueSet,omitempty"`
 }
 
 // WorkspaceSymbolClientCapabilitiesTagSupport - Supported workspace symbol tags.
 type WorkspaceSymbolClientCapabilitiesTagSupport struct {
 	// ValueSet is the symbol tag values the client supports.
-	ValueSet []uint32 `json:"valueSet,omitempty"`
+	ValueSet []SymbolTag `json:"valueSet,omitempty"`
 }
 
 // WorkspaceSymbolClientCapabilities - Workspace symbol capabilities.
@@ -183,6 +194,14 @@ type CodeLensWorkspaceClientCapabilities struct {
 	RefreshSupport bool `json:"refreshSupport,omitempty"`
 }
 
+// FoldingRangeWorkspaceClientCapabilities - Workspace folding range capabilities.
+//
+// @since 3.18.0
+type FoldingRangeWorkspaceClientCapabilities struct {
+	// RefreshSupport indicates support for `workspace/foldingRange/refresh`.
+	RefreshSupport bool `json:"refreshSupport,omitempty"`
+}
+
 // InlineValueWorkspaceClientCapabilities - Workspace inline value capabilities.
 type InlineValueWorkspaceClientCapabilities struct {
 	// RefreshSupport indicates support for `workspace/inlineValue/refresh`.
@@ -236,6 +255,8 @@ type WorkspaceClientCapabilities struct {
 	InlayHint *InlayHintWorkspaceClientCapabilities `json:"inlayHint,omitempty"`
 	// Diagnostics describes workspace diagnostic refresh support.
 	Diagnostics *DiagnosticWorkspaceClientCapabilities `json:"diagnostics,omitempty"`
+	// FoldingRange describes workspace folding range refresh support.
+	FoldingRange *FoldingRangeWorkspaceClientCapabilities `json:"foldingRange,omitempty"`
 }
 
 // TextDocumentSyncClientCapabilities - Synchronization capabilities.
@@ -467,7 +488,7 @@ type PublishDiagnosticsClientCapabilities struct {
 // FoldingRangeKindClientCapabilities - Folding range kind support.
 type FoldingRangeKindClientCapabilities struct {
 	// ValueSet is the folding range kinds supported by the client.
-	ValueSet []string `json:"valueSet,omitempty"`
+	ValueSet []FoldingRangeKind `json:"valueSet,omitempty"`
 }
 
 // FoldingRangeClientCapabilities - Folding range capabilities.
@@ -20,18 +20,18 @@ type ServerCapabilities struct {
 	// This can be either:
 	// - `TextDocumentSyncOptions`, or
 	// - `TextDocumentSyncKind` (for backwards compatibility).
-	TextDocumentSync any `json:"textDocumentSync,omitempty"` // TextDocumentSyncOptions | TextDocumentSyncKind
+	TextDocumentSync *TextDocumentSyncOptionsOrKind `json:"textDocumentSync,omitempty"`
 
 	// Defines notebook document synchronization support.
 	//
 	// @since 3.17.0
-	NotebookDocumentSync any `json:"notebookDocumentSync,omitempty"` // NotebookDocumentSyncOptions | NotebookDocumentSyncRegistrationOptions
+	NotebookDocumentSync *OptionsOrRegistration[NotebookDocumentSyncOptions] `json:"notebookDocumentSync,omitempty"` // NotebookDocumentSyncOptions | NotebookDocumentSyncRegistrationOptions
 
 	// The server provides completion support.
 	CompletionProvider *CompletionOptions `json:"completionProvider,omitempty"`
 
 	// The server provides hover support.
-	HoverProvider any `json:"hoverProvider,omitempty"` // bool | HoverOptions
+	HoverProvider *BoolOrOptions[HoverOptions] `json:"hoverProvider,omitempty"` // bool | HoverOptions
 
 	// The server provides signature help support.
 	SignatureHelpProvider *SignatureHelpOptions `json:"signatureHelpProvider,omitempty"`
@@ -39,32 +39,32 @@ type ServerCapabilities struct {
 	// The server provides goto declaration support.
 	//
 	// @since 3.14.0
-	DeclarationProvider any `json:"declarationProvider,omitempty"` // bool | DeclarationOptions | DeclarationRegistrationOptions
+	DeclarationProvider *BoolOrOptions[DeclarationOptions] `json:"declarationProvider,omitempty"` // bool | DeclarationOptions | DeclarationRegistrationOptions
 
 	// The server provides goto definition support.
-	DefinitionProvider any `json:"definitionProvider,omitempty"` // bool | DefinitionOptions
+	DefinitionProvider *BoolOrOptions[DefinitionOptions] `json:"definitionProvider,omitempty"` // bool | DefinitionOptions
 
 	// The server provides goto type definition support.
 	//
 	// @since 3.6.0
-	TypeDefinitionProvider any `json:"typeDefinitionProvider,omitempty"` // bool | TypeDefinitionOptions | TypeDefinitionRegistrationOptions
+	TypeDefinitionProvider *BoolOrOptions[TypeDefinitionOptions] `json:"typeDefinitionProvider,omitempty"` // bool | TypeDefinitionOptions | TypeDefinitionRegistrationOptions
 
 	// The server provides goto implementation support.
 	//
 	// @since 3.6.0
-	ImplementationProvider any `json:"implementationProvider,omitempty"` // bool | ImplementationOptions | ImplementationRegistrationOptions
+	ImplementationProvider *BoolOrOptions[ImplementationOptions] `json:"implementationProvider,omitempty"` // bool | ImplementationOptions | ImplementationRegistrationOptions
 
 	// The server provides find references support.
-	ReferencesProvider any `json:"referencesProvider,omitempty"` // bool | ReferenceOptions
+	ReferencesProvider *BoolOrOptions[ReferenceOptions] `json:"referencesProvider,omitempty"` // bool | ReferenceOptions
 
 	// The server provides document highlight support.
-	DocumentHighlightProvider any `json:"documentHighlightProvider,omitempty"` // bool | DocumentHighlightOptions
+	DocumentHighlightProvider *BoolOrOptions[DocumentHighlightOptions] `json:"documentHighlightProvider,omitempty"` // bool | DocumentHighlightOptions
 
 	// The server provides document symbol support.
-	DocumentSymbolProvider any `json:"documentSymbolProvider,omitempty"` // bool | DocumentSymbolOptions
+	DocumentSymbolProvider *BoolOrOptions[DocumentSymbolOptions] `json:"documentSymbolProvider,omitempty"` // bool | DocumentSymbolOptions
 
 	// The server provides code action support.
-	CodeActionProvider any `json:"codeActionProvider,omitempty"` // bool | CodeActionOptions
+	CodeActionProvider *BoolOrOptions[CodeActionOptions] `json:"codeActionProvider,omitempty"` // bool | CodeActionOptions
 
 	// The server provides code lens support.
 	CodeLensProvider *CodeLensOptions `json:"codeLensProvider,omitempty"`
@@ -75,24 +75,24 @@ type ServerCapabilities struct {
 	// The server provides color provider support.
 	//
 	// @since 3.6.0
-	ColorProvider any `json:"colorProvider,omitempty"` // bool | DocumentColorOptions | DocumentColorRegistrationOptions
+	ColorProvider *BoolOrOptions[DocumentColorOptions] `json:"colorProvider,omitempty"` // bool | DocumentColorOptions | DocumentColorRegistrationOptions
 
 	// The server provides document formatting support.
-	DocumentFormattingProvider any `json:"documentFormattingProvider,omitempty"` // bool | DocumentFormattingOptions
+	DocumentFormattingProvider *BoolOrOptions[DocumentFormattingOptions] `json:"documentFormattingProvider,omitempty"` // bool | DocumentFormattingOptions
 
 	// The server provides document range formatting support.
-	DocumentRangeFormattingProvider any `json:"documentRangeFormattingProvider,omitempty"` // bool | DocumentRangeFormattingOptions
+	DocumentRangeFormattingProvider *BoolOrOptions[DocumentRangeFormattingOptions] `json:"documentRangeFormattingProvider,omitempty"` // bool | DocumentRangeFormattingOptions
 
 	// The server provides document on type formatting support.
 	DocumentOnTypeFormattingProvider *DocumentOnTypeFormattingOptions `json:"documentOnTypeFormattingProvider,omitempty"`
 
 	// The server provides rename support.
-	RenameProvider any `json:"renameProvider,omitempty"` // bool | RenameOptions
+	RenameProvider *BoolOrOptions[RenameOptions] `json:"renameProvider,omitempty"` // bool | RenameOptions
 
 	// The server provides folding range support.
 	//
 	// @since 3.10.0
-	FoldingRangeProvider any `json:"foldingRangeProvider,omitempty"` // bool | FoldingRangeOptions | FoldingRangeRegistrationOptions
+	FoldingRangeProvider *BoolOrOptions[FoldingRangeOptions] `json:"foldingRangeProvider,omitempty"` // bool | FoldingRangeOptions | FoldingRangeRegistrationOptions
 
 	// The server provides execute command support.
 	ExecuteCommandProvider *ExecuteCommandOptions `json:"executeCommandProvider,omitempty"`
@@ -100,52 +100,52 @@ type ServerCapabilities struct {
 	// The server provides selection range support.
 	//
 	// @since 3.15.0
-	SelectionRangeProvider any `json:"selectionRangeProvider,omitempty"` // bool | SelectionRangeOptions | SelectionRangeRegistrationOptions
+	SelectionRangeProvider *BoolOrOptions[SelectionRangeOptions] `json:"selectionRangeProvider,omitempty"` // bool | SelectionRangeOptions | SelectionRangeRegistrationOptions
 
 	// The server provides linked editing range support.
 	//
 	// @since 3.16.0
-	LinkedEditingRangeProvider any `json:"linkedEditingRangeProvider,omitempty"` // bool | LinkedEditingRangeOptions | LinkedEditingRangeRegistrationOptions
+	LinkedEditingRangeProvider *BoolOrOptions[LinkedEditingRangeOptions] `json:"linkedEditingRangeProvider,omitempty"` // bool | LinkedEditingRangeOptions | LinkedEditingRangeRegistrationOptions
 
 	// The server provides call hierarchy support.
 	//
 	// @since 3.16.0
-	CallHierarchyProvider any `json:"callHierarchyProvider,omitempty"` // bool | CallHierarchyOptions | CallHierarchyRegistrationOptions
+	CallHierarchyProvider *BoolOrOptions[CallHierarchyOptions] `json:"callHierarchyProvider,omitempty"` // bool | CallHierarchyOptions | CallHierarchyRegistrationOptions
 
 	// The server provides semantic tokens support.
 	//
 	// @since 3.16.0
-	SemanticTokensProvider any `json:"semanticTokensProvider,omitempty"` // SemanticTokensOptions | SemanticTokensRegistrationOptions
+	SemanticTokensProvider *OptionsOrRegistration[SemanticTokensOptions] `json:"semanticTokensProvider,omitempty"` // SemanticTokensOptions | SemanticTokensRegistrationOptions
 
 	// The server provides moniker support.
 	//
 	// @since 3.16.0
-	MonikerProvider any `json:"monikerProvider,omitempty"` // bool | MonikerOptions | MonikerRegistrationOptions
+	MonikerProvider *BoolOrOptions[MonikerOptions] `json:"monikerProvider,omitempty"` // bool | MonikerOptions | MonikerRegistrationOptions
 
 	// The server provides type hierarchy support.
 	//
 	// @since 3.17.0
-	TypeHierarchyProvider any `json:"typeHierarchyProvider,omitempty"` // bool | TypeHierarchyOptions | TypeHierarchyRegistrationOptions
+	TypeHierarchyProvider *BoolOrOptions[TypeHierarchyOptions] `json:"typeHierarchyProvider,omitempty"` // bool | TypeHierarchyOptions | TypeHierarchyRegistrationOptions
 
 	// The server provides inline value support.
 	//
 	// @since 3.17.0
-	InlineValueProvider any `json:"inlineValueProvider,omitempty"` // bool | InlineValueOptions | InlineValueRegistrationOptions
+	InlineValueProvider *BoolOrOptions[InlineValueOptions] `json:"inlineValueProvider,omitempty"` // bool | InlineValueOptions | InlineValueRegistrationOptions
 
 	// The server provides inlay hint support.
 	//
 	// @since 3.17.0
-	InlayHintProvider any `json:"inlayHintProvider,omitempty"` // bool | InlayHintOptions | InlayHintRegistrationOptions
+	InlayHintProvider *BoolOrOptions[InlayHintOptions] `json:"inlayHintProvider,omitempty"` // bool | InlayHintOptions | InlayHintRegistrationOptions
 
 	// The server has support for pull model diagnostics.
 	//
 	// @since 3.17.0
-	DiagnosticProvider any `json:"diagnosticProvider,omitempty"` // DiagnosticOptions | DiagnosticRegistrationOptions
+	DiagnosticProvider *OptionsOrRegistration[DiagnosticOptions] `json:"diagnosticProvider,omitempty"` // DiagnosticOptions | DiagnosticRegistrationOptions
 
 	// The server provides workspace symbol support.
 	//
 	// @since 3.17.0
-	WorkspaceSymbolProvider any `json:"workspaceSymbolProvider,omitempty"` // bool | WorkspaceSymbolOptions
+	WorkspaceSymbolProvider *BoolOrOptions[WorkspaceSymbolOptions] `json:"workspaceSymbolProvider,omitempty"` // bool | WorkspaceSymbolOptions
 
 	// Workspace-specific server capabilities.
 	Workspace *WorkspaceServerCapabilities `json:"workspace,omitempty"`
@@ -177,11 +177,11 @@ const (
 //
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocumentSyncOptions
 type TextDocumentSyncOptions struct {
-	OpenClose         bool                 `json:"openClose,omitempty"`
-	Change            TextDocumentSyncKind `json:"change,omitempty"`
-	WillSave          bool                 `json:"willSave,omitempty"`
-	WillSaveWaitUntil bool                 `json:"willSaveWaitUntil,omitempty"`
-	Save              any                  `json:"save,omitempty"` // bool | SaveOptions
+	OpenClose         bool                        `json:"openClose,omitempty"`
+	Change            TextDocumentSyncKind        `json:"change,omitempty"`
+	WillSave          bool                        `json:"willSave,omitempty"`
+	WillSaveWaitUntil bool                        `json:"willSaveWaitUntil,omitempty"`
+	Save              *BoolOrOptions[SaveOptions] `json:"save,omitempty"` // bool | SaveOptions
 }
 
 // SaveOptions options for save notifications.
@@ -295,7 +295,14 @@ type DocumentColorOptions = WorkDoneProgressOptions
 type DocumentFormattingOptions = WorkDoneProgressOptions
 
 // DocumentRangeFormattingOptions describes range formatting support details.
-type DocumentRangeFormattingOptions = WorkDoneProgressOptions
+type DocumentRangeFormattingOptions struct {
+	WorkDoneProgressOptions
+
+	// Whether the server supports formatting multiple ranges at once.
+	//
+	// @since 3.18.0
+	RangesSupport bool `json:"rangesSupport,omitempty"`
+}
 
 // DocumentOnTypeFormattingOptions server capability for on-type formatting.
 type DocumentOnTypeFormattingOptions struct {
@@ -350,14 +357,20 @@ type SemanticTokensOptionsFull struct {
 	Delta bool `json:"delta,omitempty"`
 }
 
+// SemanticTokensOptionsRange indicates support for range semantic tokens.
+// The specification does not define any properties yet.
+//
+// @since 3.16.0
+type SemanticTokensOptionsRange struct{}
+
 // SemanticTokensOptions server capability for semantic tokens.
 //
 // @since 3.16.0
 type SemanticTokensOptions struct {
 	WorkDoneProgressOptions
-	Legend SemanticTokensLegend `json:"legend"`
-	Range  any                  `json:"range,omitempty"` // bool | map[string]any
-	Full   any                  `json:"full,omitempty"`  // bool | SemanticTokensOptionsFull
+	Legend SemanticTokensLegend                       `json:"legend"`
+	Range  *BoolOrOptions[SemanticTokensOptionsRange] `json:"range,omitempty"` // bool | SemanticTokensOptionsRange
+	Full   *BoolOrOptions[SemanticTokensOptionsFull]  `json:"full,omitempty"`  // bool | SemanticTokensOptionsFull
 }
 
 // MonikerOptions describes moniker support details.
@@ -57,22 +57,24 @@ func Test_CapabilitiesServer_UnmarshalValidJSON(t *testing.T) {
 		t.Fatalf("expected completionProvider.completionItem.labelDetailsSupport=true")
 	}
 
-	textDocumentSync, ok := decoded.TextDocumentSync.(float64)
-	if !ok || int(textDocumentSync) != int(protocol.TextDocumentSyncKindIncremental) {
+	if decoded.TextDocumentSync == nil || decoded.TextDocumentSync.Options != nil || decoded.TextDocumentSync.Kind != protocol.TextDocumentSyncKindIncremental {
 		t.Fatalf("expected textDocumentSync=%d, got %#v", protocol.TextDocumentSyncKindIncremental, decoded.TextDocumentSync)
 	}
 
-	diagnosticProvider, ok := decoded.DiagnosticProvider.(map[string]any)
-	if !ok {
-		t.Fatalf("expected diagnosticProvider object, got %#v", decoded.DiagnosticProvider)
+	if !decoded.HoverProvider.Enabled() || decoded.HoverProvider.Options != nil {
+		t.Fatalf("expected hoverProvider=true, got %#v", decoded.HoverProvider)
+	}
+
+	if decoded.DiagnosticProvider == nil {
+		t.Fatalf("expected diagnosticProvider object")
 	}
 
-	if inter, ok := diagnosticProvider["interFileDependencies"].(bool); !ok || !inter {
-		t.Fatalf("expected diagnosticProvider.interFileDependencies=true, got %#v", diagnosticProvider["interFileDependencies"])
+	if !decoded.DiagnosticProvider.Options.InterFileDependencies {
+		t.Fatalf("expected diagnosticProvider.interFileDependencies=true, got %#v", decoded.DiagnosticProvider.Options)
 	}
 
-	if ws, ok := diagnosticProvider["workspaceDiagnostics"].(bool); !ok || !ws {
-		t.Fatalf("expected diagnosticProvider.workspaceDiagnostics=true, got %#v", diagnosticProvider["workspaceDiagnostics"])
+	if !decoded.DiagnosticProvider.Options.WorkspaceDiagnostics {
+		t.Fatalf("expected diagnosticProvider.workspaceDiagnostics=true, got %#v", decoded.DiagnosticProvider.Options)
 	}
 }
 
@@ -176,30 +178,55 @@ func Test_CapabilitiesServer_UnmarshalExtendedFields(t *testing.T) {
 		t.Fatalf("expected workspace.workspaceFolders.supported=true")
 	}
 
-	tds, ok := decoded.TextDocumentSync.(map[string]any)
-	if !ok {
-		t.Fatalf("expected textDocumentSync object, got %#v", decoded.TextDocumentSync)
+	tds := decoded.TextDocumentSync
+	if tds == nil || tds.Options == nil {
+		t.Fatalf("expected textDocumentSync object, got %#v", tds)
 	}
 
-	if openClose, ok := tds["openClose"].(bool); !ok || !openClose {
-		t.Fatalf("expected textDocumentSync.openClose=true, got %#v", tds["openClose"])
+	if !tds.Options.OpenClose {
+		t.Fatalf("expected textDocumentSync.openClose=true, got %#v", tds.Options)
 	}
 
-	hover, ok := decoded.HoverProvider.(map[string]any)
-	if !ok {
-		t.Fatalf("expected hoverProvider object, got %#v", decoded.HoverProvider)
+	if tds.SyncKind() != protocol.TextDocumentSyncKindIncremental {
+		t.Fatalf("expected textDocumentSync.change=%d, got %d", protocol.TextDocumentSyncKindIncremental, tds.SyncKind())
+	}
+
+	if tds.Options.Save == nil || tds.Options.Save.Options == nil || !tds.Options.Save.Options.IncludeText {
+		t.Fatalf("expected textDocumentSync.save.includeText=true, got %#v", tds.Options.Save)
+	}
+
+	if decoded.NotebookDocumentSync == nil || !decoded.NotebookDocumentSync.Options.Save {
+		t.Fatalf("expected notebookDocumentSync.save=true, got %#v", decoded.NotebookDocumentSync)
+	}
+
+	hover := decoded.HoverProvider
+	if hover == nil || hover.Options == nil {
+		t.Fatalf("expected hoverProvider object, got %#v", hover)
+	}
+
+	if !hover.Options.WorkDoneProgress {
+		t.Fatalf("expected hoverProvider.workDoneProgress=true, got %#v", hover.Options)
 	}
 
-	if wdp, ok := hover["workDoneProgress"].(bool); !ok || !wdp {
-		t.Fatalf("expected hoverProvider.workDoneProgress=true, got %#v", hover["workDoneProgress"])
+	if decl := decoded.DeclarationProvider; decl == nil || !decl.Bool || decl.Options != nil {
+		t.Fatalf("expected declarationProvider=true, got %#v", decl)
 	}
 
-	if decl, ok := decoded.DeclarationProvider.(bool); !ok || !decl {
-		t.Fatalf("expected declarationProvider=true, got %#v", decoded.DeclarationProvider)
+	if ca := decoded.CodeActionProvider; ca == nil || ca.Options == nil || !ca.Options.ResolveProvider {
+		t.Fatalf("expected codeActionProvider.resolveProvider=true, got %#v", ca)
 	}
 
-	if sem, ok := decoded.SemanticTokensProvider.(map[string]any); !ok || sem["legend"] == nil {
-		t.Fatalf("expected semanticTokensProvider.legend, got %#v", decoded.SemanticTokensProvider)
+	if rename := decoded.RenameProvider; rename == nil || rename.Options == nil || !rename.Options.PrepareProvider {
+		t.Fatalf("expected renameProvider.prepareProvider=true, got %#v", rename)
+	}
+
+	sem := decoded.SemanticTokensProvider
+	if sem == nil || len(sem.Options.Legend.TokenTypes) != 1 || sem.Options.Legend.TokenTypes[0] != "class" {
+		t.Fatalf("expected semanticTokensProvider.legend, got %#v", sem)
+	}
+
+	if !sem.Options.Full.Enabled() || sem.Options.Range.Enabled() {
+		t.Fatalf("expected semanticTokensProvider full=true range=unset, got %#v", sem.Options)
 	}
 }
 
@@ -207,24 +234,26 @@ func Test_CapabilitiesServer_MarshalExtendedFields(t *testing.T) {
 	resolve := true
 	original := protocol.ServerCapabilities{
 		PositionEncoding: &[]protocol.PositionEncodingKind{protocol.PositionEncodingKindUTF16}[0],
-		TextDocumentSync: protocol.TextDocumentSyncOptions{
-			OpenClose: true,
-			Change:    protocol.TextDocumentSyncKindIncremental,
-			Save:      protocol.SaveOptions{IncludeText: true},
+		TextDocumentSync: &protocol.TextDocumentSyncOptionsOrKind{
+			Options: &protocol.TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    protocol.TextDocumentSyncKindIncremental,
+				Save:      protocol.OptionsProvider(protocol.SaveOptions{IncludeText: true}),
+			},
 		},
-		HoverProvider: protocol.HoverOptions{WorkDoneProgress: true},
+		HoverProvider: protocol.OptionsProvider(protocol.HoverOptions{WorkDoneProgress: true}),
 		CompletionProvider: &protocol.CompletionOptions{
 			ResolveProvider: &resolve,
 			CompletionItem: &protocol.CompletionOptionsCompletionItem{
 				LabelDetailsSupport: &resolve,
 			},
 		},
-		DeclarationProvider: protocol.DeclarationOptions{WorkDoneProgress: true},
-		DefinitionProvider:  true,
-		CodeActionProvider: protocol.CodeActionOptions{
+		DeclarationProvider: protocol.OptionsProvider(protocol.DeclarationOptions{WorkDoneProgress: true}),
+		DefinitionProvider:  protocol.BoolProvider[protocol.DefinitionOptions](true),
+		CodeActionProvider: protocol.OptionsProvider(protocol.CodeActionOptions{
 			CodeActionKinds: []protocol.CodeActionKind{protocol.CodeActionQuickFix},
 			ResolveProvider: true,
-		},
+		}),
 		CodeLensProvider: &protocol.CodeLensOptions{ResolveProvider: true},
 		DocumentLinkProvider: &protocol.DocumentLinkOptions{
 			ResolveProvider: true,
@@ -233,22 +262,26 @@ func Test_CapabilitiesServer_MarshalExtendedFields(t *testing.T) {
 			FirstTriggerCharacter: ";",
 			MoreTriggerCharacter:  []string{","},
 		},
-		RenameProvider: protocol.RenameOptions{PrepareProvider: true},
+		RenameProvider: protocol.OptionsProvider(protocol.RenameOptions{PrepareProvider: true}),
 		ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
 			Commands: []string{"laravel.run"},
 		},
-		SemanticTokensProvider: protocol.SemanticTokensOptions{
-			Legend: protocol.SemanticTokensLegend{
-				TokenTypes:     []string{"class"},
-				TokenModifiers: []string{"declaration"},
+		SemanticTokensProvider: &protocol.OptionsOrRegistration[protocol.SemanticTokensOptions]{
+			Options: protocol.SemanticTokensOptions{
+				Legend: protocol.SemanticTokensLegend{
+					TokenTypes:     []string{"class"},
+					TokenModifiers: []string{"declaration"},
+				},
+				Full: protocol.BoolProvider[protocol.SemanticTokensOptionsFull](true),
 			},
-			Full: true,
 		},
-		DiagnosticProvider: protocol.DiagnosticOptions{
-			InterFileDependencies: true,
-			WorkspaceDiagnostics:  true,
+		DiagnosticProvider: &protocol.OptionsOrRegistration[protocol.DiagnosticOptions]{
+			Options: protocol.DiagnosticOptions{
+				InterFileDependencies: true,
+				WorkspaceDiagnostics:  true,
+			},
 		},
-		WorkspaceSymbolProvider: protocol.WorkspaceSymbolOptions{ResolveProvider: true},
+		WorkspaceSymbolProvider: protocol.OptionsProvider(protocol.WorkspaceSymbolOptions{ResolveProvider: true}),
 		Workspace: &protocol.WorkspaceServerCapabilities{
 			WorkspaceFolders: &protocol.WorkspaceFoldersServerCapabilities{
 				Supported:           true,
@@ -319,3 +352,76 @@ func Test_CapabilitiesServer_MarshalExtendedFields(t *testing.T) {
 		t.Fatalf("expected workspace.fileOperations.didCreate in payload: %s", string(data))
 	}
 }
+
+func Test_CapabilitiesServer_BoolOrOptionsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "true", json: `true`},
+		{name: "false", json: `false`},
+		{name: "options", json: `{"workDoneProgress":true}`},
+		{name: "registration", json: `{"documentSelector":[{"language":"php"}],"id":"hover","workDoneProgress":true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded protocol.BoolOrOptions[protocol.HoverOptions]
+			if err := json.Unmarshal([]byte(tt.json), &decoded); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+
+			data, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+
+			if string(data) != tt.json {
+				t.Fatalf("expected %s, got %s", tt.json, string(data))
+			}
+		})
+	}
+}
+
+func Test_CapabilitiesServer_BoolOrOptionsRegistration(t *testing.T) {
+	data := []byte(`{"foldingRangeProvider": {"documentSelector": [{"language": "php"}], "id": "folding"}}`)
+
+	var decoded protocol.ServerCapabilities
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	folding := decoded.FoldingRangeProvider
+	if !folding.Enabled() {
+		t.Fatalf("expected foldingRangeProvider to be enabled, got %#v", folding)
+	}
+
+	if folding.Registration == nil || folding.Registration.ID != "folding" || folding.Registration.DocumentSelector == nil {
+		t.Fatalf("expected foldingRangeProvider registration, got %#v", folding.Registration)
+	}
+}
+
+func Test_CapabilitiesServer_BoolOrOptionsInvalid(t *testing.T) {
+	var decoded protocol.BoolOrOptions[protocol.HoverOptions]
+	if err := json.Unmarshal([]byte(`"yes"`), &decoded); err == nil {
+		t.Fatalf("expected error for string provider value")
+	}
+}
+
+func Test_CapabilitiesServer_TextDocumentSyncRoundTrip(t *testing.T) {
+	for _, input := range []string{`1`, `{"openClose":true,"change":2,"save":true}`} {
+		var decoded protocol.TextDocumentSyncOptionsOrKind
+		if err := json.Unmarshal([]byte(input), &decoded); err != nil {
+			t.Fatalf("unmarshal %s failed: %v", input, err)
+		}
+
+		data, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+
+		if string(data) != input {
+			t.Fatalf("expected %s, got %s", input, string(data))
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// StaticRegistrationOptions static registration options to be returned in the
+// initialize request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#staticRegistrationOptions
+type StaticRegistrationOptions struct {
+	// The id used to register the request. The id can be used to deregister
+	// the request again. See also Registration#id.
+	ID string `json:"id,omitempty"`
+}
+
+// ProviderRegistration holds the registration fields a server may add to a
+// provider's options in `ServerCapabilities` (the `XRegistrationOptions`
+// variants of the specification).
+type ProviderRegistration struct {
+	TextDocumentRegistrationOptions
+	StaticRegistrationOptions
+}
+
+func (r *ProviderRegistration) empty() bool {
+	return r == nil || (r.DocumentSelector == nil && r.ID == "")
+}
+
+// BoolOrOptions is a `boolean | T | T & RegistrationOptions` provider
+// capability.
+//
+// A plain boolean is stored in Bool. An object is decoded into Options, with
+// any `documentSelector` or `id` stored in Registration.
+type BoolOrOptions[T any] struct {
+	Bool         bool
+	Options      *T
+	Registration *ProviderRegistration
+}
+
+// BoolProvider returns a provider capability sent as a plain boolean.
+func BoolProvider[T any](enabled bool) *BoolOrOptions[T] {
+	return &BoolOrOptions[T]{Bool: enabled}
+}
+
+// OptionsProvider returns a provider capability sent as an options object.
+func OptionsProvider[T any](options T) *BoolOrOptions[T] {
+	return &BoolOrOptions[T]{Options: &options}
+}
+
+// Enabled reports whether the provider is supported, either because it is
+// `true` or because options were given.
+func (b *BoolOrOptions[T]) Enabled() bool {
+	return b != nil && (b.Bool || b.Options != nil)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b BoolOrOptions[T]) MarshalJSON() ([]byte, error) {
+	if b.Options == nil {
+		if !b.Registration.empty() {
+			var options T
+			return marshalWithRegistration(&options, b.Registration)
+		}
+		return json.Marshal(b.Bool)
+	}
+	return marshalWithRegistration(b.Options, b.Registration)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *BoolOrOptions[T]) UnmarshalJSON(data []byte) error {
+	*b = BoolOrOptions[T]{}
+
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '{' {
+		if err := json.Unmarshal(data, &b.Bool); err != nil {
+			return errors.New("invalid provider capability: not a boolean or options object")
+		}
+		return nil
+	}
+
+	var options T
+	if err := json.Unmarshal(data, &options); err != nil {
+		return err
+	}
+	b.Options = &options
+
+	registration, err := unmarshalRegistration(data)
+	if err != nil {
+		return err
+	}
+	b.Registration = registration
+	return nil
+}
+
+// OptionsOrRegistration is a `T | T & RegistrationOptions` provider
+// capability, used by providers that cannot be announced with a boolean.
+type OptionsOrRegistration[T any] struct {
+	Options      T
+	Registration *ProviderRegistration
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o OptionsOrRegistration[T]) MarshalJSON() ([]byte, error) {
+	return marshalWithRegistration(&o.Options, o.Registration)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *OptionsOrRegistration[T]) UnmarshalJSON(data []byte) error {
+	*o = OptionsOrRegistration[T]{}
+
+	if err := json.Unmarshal(data, &o.Options); err != nil {
+		return err
+	}
+
+	registration, err := unmarshalRegistration(data)
+	if err != nil {
+		return err
+	}
+	o.Registration = registration
+	return nil
+}
+
+// TextDocumentSyncOptionsOrKind is the `TextDocumentSyncOptions |
+// TextDocumentSyncKind` value of `ServerCapabilities.TextDocumentSync`.
+type TextDocumentSyncOptionsOrKind struct {
+	Kind    TextDocumentSyncKind
+	Options *TextDocumentSyncOptions
+}
+
+// SyncKind returns the text document sync kind, taken from the options'
+// change field when options were given.
+func (t *TextDocumentSyncOptionsOrKind) SyncKind() TextDocumentSyncKind {
+	if t == nil {
+		return TextDocumentSyncKindNone
+	}
+	if t.Options != nil {
+		return t.Options.Change
+	}
+	return t.Kind
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t TextDocumentSyncOptionsOrKind) MarshalJSON() ([]byte, error) {
+	if t.Options != nil {
+		return json.Marshal(t.Options)
+	}
+	return json.Marshal(t.Kind)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *TextDocumentSyncOptionsOrKind) UnmarshalJSON(data []byte) error {
+	*t = TextDocumentSyncOptionsOrKind{}
+
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		var options TextDocumentSyncOptions
+		if err := json.Unmarshal(data, &options); err != nil {
+			return err
+		}
+		t.Options = &options
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &t.Kind); err != nil {
+		return errors.New("invalid textDocumentSync: not a TextDocumentSyncKind or TextDocumentSyncOptions")
+	}
+	return nil
+}
+
+// marshalWithRegistration marshals options as an object and merges the
+// registration fields into it.
+func marshalWithRegistration(options any, registration *ProviderRegistration) ([]byte, error) {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	if registration.empty() {
+		return data, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	extra, err := json.Marshal(registration)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(extra, &fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// unmarshalRegistration decodes the registration fields of an options object,
+// returning nil when none are present.
+func unmarshalRegistration(data []byte) (*ProviderRegistration, error) {
+	var registration ProviderRegistration
+	if err := json.Unmarshal(data, &registration); err != nil {
+		return nil, err
+	}
+	if registration.empty() {
+		return nil, nil
+	}
+	return &registration, nil
+}
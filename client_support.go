@@ -0,0 +1,388 @@
+package protocol
+
+// ClientSupport answers questions about the features a client supports,
+// based on the capabilities it sent in the `initialize` request.
+//
+// Absent capabilities are resolved to the defaults defined by the specification.
+type ClientSupport struct {
+	caps ClientCapabilities
+}
+
+// NewClientSupport creates a ClientSupport from the initialize parameters.
+func NewClientSupport(params InitializeParams) ClientSupport {
+	return ClientSupport{caps: params.Capabilities}
+}
+
+// Capabilities returns the raw client capabilities.
+func (s ClientSupport) Capabilities() ClientCapabilities {
+	return s.caps
+}
+
+func (s ClientSupport) textDocument() *TextDocumentClientCapabilities {
+	if s.caps.TextDocument == nil {
+		return &TextDocumentClientCapabilities{}
+	}
+	return s.caps.TextDocument
+}
+
+func (s ClientSupport) workspace() *WorkspaceClientCapabilities {
+	if s.caps.Workspace == nil {
+		return &WorkspaceClientCapabilities{}
+	}
+	return s.caps.Workspace
+}
+
+func (s ClientSupport) completionItem() *CompletionItemClientCapabilities {
+	if c := s.textDocument().Completion; c != nil && c.CompletionItem != nil {
+		return c.CompletionItem
+	}
+	return &CompletionItemClientCapabilities{}
+}
+
+// Snippets reports whether the client supports snippets as insert text in completion items.
+func (s ClientSupport) Snippets() bool {
+	return s.completionItem().SnippetSupport
+}
+
+// CompletionLabelDetails reports whether the client supports `CompletionItemLabelDetails`.
+//
+// @since 3.17.0
+func (s ClientSupport) CompletionLabelDetails() bool {
+	return s.completionItem().LabelDetailsSupport
+}
+
+// CompletionItemKind reports whether the client supports the completion item kind.
+//
+// If the client does not announce the kinds it supports, only the kinds
+// from `Text` to `Reference` defined in the initial version of the protocol
+// are supported.
+func (s ClientSupport) CompletionItemKind(kind CompletionItemKind) bool {
+	if c := s.textDocument().Completion; c != nil && c.CompletionItemKind != nil && c.CompletionItemKind.ValueSet != nil {
+		for _, k := range c.CompletionItemKind.ValueSet {
+			if k == kind {
+				return true
+			}
+		}
+		return false
+	}
+	return kind >= CompletionItemKindText && kind <= CompletionItemKindReference
+}
+
+// CompletionDocumentationFormat returns the preferred content format for completion item documentation.
+func (s ClientSupport) CompletionDocumentationFormat() MarkupKind {
+	return preferredMarkupKind(s.completionItem().DocumentationFormat)
+}
+
+// HoverFormat returns the preferred content format for hover contents.
+func (s ClientSupport) HoverFormat() MarkupKind {
+	if h := s.textDocument().Hover; h != nil {
+		return preferredMarkupKind(h.ContentFormat)
+	}
+	return MarkupKindPlainText
+}
+
+// MarkdownHover reports whether the client can render markdown hover contents.
+func (s ClientSupport) MarkdownHover() bool {
+	if h := s.textDocument().Hover; h != nil {
+		return containsMarkupKind(h.ContentFormat, MarkupKindMarkdown)
+	}
+	return false
+}
+
+// SignatureDocumentationFormat returns the preferred content format for signature help documentation.
+func (s ClientSupport) SignatureDocumentationFormat() MarkupKind {
+	if h := s.textDocument().SignatureHelp; h != nil && h.SignatureInformation != nil {
+		return preferredMarkupKind(h.SignatureInformation.DocumentationFormat)
+	}
+	return MarkupKindPlainText
+}
+
+// DefinitionLinks reports whether the client supports `LocationLink` results for definition requests.
+//
+// @since 3.14.0
+func (s ClientSupport) DefinitionLinks() bool {
+	if d := s.textDocument().Definition; d != nil {
+		return d.LinkSupport
+	}
+	return false
+}
+
+// HierarchicalDocumentSymbols reports whether the client supports hierarchical document symbols.
+func (s ClientSupport) HierarchicalDocumentSymbols() bool {
+	if d := s.textDocument().DocumentSymbol; d != nil {
+		return d.HierarchicalDocumentSymbolSupport
+	}
+	return false
+}
+
+// PrepareRename reports whether the client supports testing for validity of rename operations.
+//
+// @since 3.12.0
+func (s ClientSupport) PrepareRename() bool {
+	if r := s.textDocument().Rename; r != nil {
+		return r.PrepareSupport
+	}
+	return false
+}
+
+// RangesFormatting reports whether the client supports formatting multiple ranges at once.
+//
+// @since 3.18.0
+func (s ClientSupport) RangesFormatting() bool {
+	if r := s.textDocument().RangeFormatting; r != nil {
+		return r.RangesSupport
+	}
+	return false
+}
+
+// DocumentLinkTooltip reports whether the client supports the tooltip of document links.
+//
+// @since 3.15.0
+func (s ClientSupport) DocumentLinkTooltip() bool {
+	if l := s.textDocument().DocumentLink; l != nil {
+		return l.TooltipSupport
+	}
+	return false
+}
+
+// WorkDoneProgress reports whether the client supports server initiated work done progress.
+//
+// @since 3.15.0
+func (s ClientSupport) WorkDoneProgress() bool {
+	if s.caps.Window != nil {
+		return s.caps.Window.WorkDoneProgress
+	}
+	return false
+}
+
+// ShowDocument reports whether the client supports the `window/showDocument` request.
+//
+// @since 3.16.0
+func (s ClientSupport) ShowDocument() bool {
+	if s.caps.Window != nil && s.caps.Window.ShowDocument != nil {
+		return s.caps.Window.ShowDocument.Support
+	}
+	return false
+}
+
+// ApplyEdit reports whether the client supports the `workspace/applyEdit` request.
+func (s ClientSupport) ApplyEdit() bool {
+	return s.workspace().ApplyEdit
+}
+
+// DocumentChanges reports whether the client supports versioned document changes in `WorkspaceEdit`s.
+func (s ClientSupport) DocumentChanges() bool {
+	if e := s.workspace().WorkspaceEdit; e != nil {
+		return e.DocumentChanges
+	}
+	return false
+}
+
+// ResourceOperation reports whether the client supports the resource operation in `WorkspaceEdit`s.
+//
+// @since 3.13.0
+func (s ClientSupport) ResourceOperation(kind ResourceOperationKind) bool {
+	if e := s.workspace().WorkspaceEdit; e != nil {
+		for _, k := range e.ResourceOperations {
+			if k == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WorkspaceFolders reports whether the client supports workspace folders.
+//
+// @since 3.6.0
+func (s ClientSupport) WorkspaceFolders() bool {
+	return s.workspace().WorkspaceFolders
+}
+
+// Configuration reports whether the client supports the `workspace/configuration` request.
+//
+// @since 3.6.0
+func (s ClientSupport) Configuration() bool {
+	return s.workspace().Configuration
+}
+
+// CodeLensRefresh reports whether the client supports the `workspace/codeLens/refresh` request.
+//
+// @since 3.16.0
+func (s ClientSupport) CodeLensRefresh() bool {
+	if c := s.workspace().CodeLens; c != nil {
+		return c.RefreshSupport
+	}
+	return false
+}
+
+// FoldingRangeRefresh reports whether the client supports the `workspace/foldingRange/refresh` request.
+//
+// @since 3.18.0
+func (s ClientSupport) FoldingRangeRefresh() bool {
+	if f := s.workspace().FoldingRange; f != nil {
+		return f.RefreshSupport
+	}
+	return false
+}
+
+// WorkspaceSymbolResolve reports whether the client can resolve the given
+// workspace symbol property lazily, e.g. `location.range`.
+//
+// @since 3.17.0
+func (s ClientSupport) WorkspaceSymbolResolve(property string) bool {
+	if sym := s.workspace().Symbol; sym != nil && sym.ResolveSupport != nil {
+		for _, p := range sym.ResolveSupport.Properties {
+			if p == property {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RetryOnContentModified reports whether the client retries the request
+// method when it fails with RPCContentModified.
+//
+// @since 3.17.0
+func (s ClientSupport) RetryOnContentModified(method string) bool {
+	if s.caps.General != nil && s.caps.General.StaleRequestSupport != nil {
+		for _, m := range s.caps.General.StaleRequestSupport.RetryOnContentModified {
+			if m == method {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PositionEncoding picks the position encoding to use with the client.
+//
+// preferred lists the encodings the server supports, most preferred first.
+// If empty, UTF-8 is preferred over UTF-32 over UTF-16. The first preferred
+// encoding offered by the client is picked. UTF-16 is returned if the client
+// offers none of them, as it must always be supported.
+//
+// The result should be returned in `ServerCapabilities.PositionEncoding`.
+//
+// @since 3.17.0
+func (s ClientSupport) PositionEncoding(preferred ...PositionEncodingKind) PositionEncodingKind {
+	if len(preferred) == 0 {
+		preferred = []PositionEncodingKind{
+			PositionEncodingKindUTF8,
+			PositionEncodingKindUTF32,
+			PositionEncodingKindUTF16,
+		}
+	}
+
+	if s.caps.General != nil {
+		for _, encoding := range preferred {
+			for _, offered := range s.caps.General.PositionEncodings {
+				if encoding == offered {
+					return encoding
+				}
+			}
+		}
+	}
+
+	return PositionEncodingKindUTF16
+}
+
+// DynamicRegistration reports whether the client supports dynamic
+// registration of the capability that serves the given request method.
+func (s ClientSupport) DynamicRegistration(method string) bool {
+	td := s.textDocument()
+	ws := s.workspace()
+
+	switch method {
+	case MethodTextDocumentDidOpen, MethodTextDocumentDidChange, MethodTextDocumentDidClose, MethodTextDocumentDidSave,
+		"textDocument/willSave", "textDocument/willSaveWaitUntil":
+		return td.Synchronization != nil && td.Synchronization.DynamicRegistration
+	case MethodTextDocumentCompletion:
+		return td.Completion != nil && td.Completion.DynamicRegistration
+	case MethodTextDocumentHover:
+		return td.Hover != nil && td.Hover.DynamicRegistration
+	case MethodTextDocumentSignatureHelp:
+		return td.SignatureHelp != nil && td.SignatureHelp.DynamicRegistration
+	case MethodTextDocumentDeclaration:
+		return td.Declaration != nil && td.Declaration.DynamicRegistration
+	case MethodTextDocumentDefinition:
+		return td.Definition != nil && td.Definition.DynamicRegistration
+	case MethodTextDocumentTypeDefinition:
+		return td.TypeDefinition != nil && td.TypeDefinition.DynamicRegistration
+	case MethodTextDocumentImplementation:
+		return td.Implementation != nil && td.Implementation.DynamicRegistration
+	case MethodTextDocumentReferences:
+		return td.References != nil && td.References.DynamicRegistration
+	case MethodTextDocumentDocumentHighlight:
+		return td.DocumentHighlight != nil && td.DocumentHighlight.DynamicRegistration
+	case MethodTextDocumentDocumentSymbol:
+		return td.DocumentSymbol != nil && td.DocumentSymbol.DynamicRegistration
+	case MethodTextDocumentCodeAction:
+		return td.CodeAction != nil && td.CodeAction.DynamicRegistration
+	case MethodTextDocumentCodeLens:
+		return td.CodeLens != nil && td.CodeLens.DynamicRegistration
+	case MethodTextDocumentDocumentLink:
+		return td.DocumentLink != nil && td.DocumentLink.DynamicRegistration
+	case MethodTextDocumentDocumentColor, MethodTextDocumentColorPresentation:
+		return td.ColorProvider != nil && td.ColorProvider.DynamicRegistration
+	case MethodTextDocumentFormatting:
+		return td.Formatting != nil && td.Formatting.DynamicRegistration
+	case MethodTextDocumentRangeFormatting, MethodTextDocumentRangesFormatting:
+		return td.RangeFormatting != nil && td.RangeFormatting.DynamicRegistration
+	case MethodTextDocumentOnTypeFormatting:
+		return td.OnTypeFormatting != nil && td.OnTypeFormatting.DynamicRegistration
+	case MethodTextDocumentRename, MethodTextDocumentPrepareRename:
+		return td.Rename != nil && td.Rename.DynamicRegistration
+	case MethodTextDocumentFoldingRange:
+		return td.FoldingRange != nil && td.FoldingRange.DynamicRegistration
+	case "textDocument/selectionRange":
+		return td.SelectionRange != nil && td.SelectionRange.DynamicRegistration
+	case "textDocument/linkedEditingRange":
+		return td.LinkedEditingRange != nil && td.LinkedEditingRange.DynamicRegistration
+	case "textDocument/prepareCallHierarchy":
+		return td.CallHierarchy != nil && td.CallHierarchy.DynamicRegistration
+	case "textDocument/semanticTokens":
+		return td.SemanticTokens != nil && td.SemanticTokens.DynamicRegistration
+	case "textDocument/moniker":
+		return td.Moniker != nil && td.Moniker.DynamicRegistration
+	case "textDocument/prepareTypeHierarchy":
+		return td.TypeHierarchy != nil && td.TypeHierarchy.DynamicRegistration
+	case "textDocument/inlineValue":
+		return td.InlineValue != nil && td.InlineValue.DynamicRegistration
+	case MethodTextDocumentInlayHint:
+		return td.InlayHint != nil && td.InlayHint.DynamicRegistration
+	case MethodTextDocumentDiagnostic:
+		return td.Diagnostic != nil && td.Diagnostic.DynamicRegistration
+	case "workspace/didChangeConfiguration":
+		return ws.DidChangeConfiguration != nil && ws.DidChangeConfiguration.DynamicRegistration
+	case "workspace/didChangeWatchedFiles":
+		return ws.DidChangeWatchedFiles != nil && ws.DidChangeWatchedFiles.DynamicRegistration
+	case MethodWorkspaceSymbol:
+		return ws.Symbol != nil && ws.Symbol.DynamicRegistration
+	case "workspace/executeCommand":
+		return ws.ExecuteCommand != nil && ws.ExecuteCommand.DynamicRegistration
+	}
+
+	return false
+}
+
+// preferredMarkupKind returns the first known markup kind in formats, which
+// the client lists in order of preference. Defaults to plain text.
+func preferredMarkupKind(formats []MarkupKind) MarkupKind {
+	for _, format := range formats {
+		if format == MarkupKindMarkdown || format == MarkupKindPlainText {
+			return format
+		}
+	}
+	return MarkupKindPlainText
+}
+
+func containsMarkupKind(formats []MarkupKind, kind MarkupKind) bool {
+	for _, format := range formats {
+		if format == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func newClientSupport(t *testing.T, capabilities string) protocol.ClientSupport {
+	t.Helper()
+
+	var params protocol.InitializeParams
+	if err := json.Unmarshal([]byte(`{"capabilities":`+capabilities+`}`), &params); err != nil {
+		t.Fatalf("unmarshal InitializeParams failed: %v", err)
+	}
+	return protocol.NewClientSupport(params)
+}
+
+func Test_ClientSupport_DefaultsForEmptyCapabilities(t *testing.T) {
+	support := newClientSupport(t, `{}`)
+
+	if support.Snippets() || support.MarkdownHover() || support.WorkDoneProgress() || support.DocumentChanges() {
+		t.Fatalf("expected features to be unsupported by default")
+	}
+
+	if support.HoverFormat() != protocol.MarkupKindPlainText {
+		t.Fatalf("expected plaintext hover format, got %s", support.HoverFormat())
+	}
+
+	if support.PositionEncoding() != protocol.PositionEncodingKindUTF16 {
+		t.Fatalf("expected utf-16, got %s", support.PositionEncoding())
+	}
+
+	if !support.CompletionItemKind(protocol.CompletionItemKindReference) || support.CompletionItemKind(protocol.CompletionItemKindFolder) {
+		t.Fatalf("expected default completion item kinds Text..Reference")
+	}
+
+	if support.DynamicRegistration(protocol.MethodTextDocumentHover) {
+		t.Fatalf("expected no dynamic registration by default")
+	}
+}
+
+func Test_ClientSupport_TextDocumentFeatures(t *testing.T) {
+	support := newClientSupport(t, `{
+		"textDocument":{
+			"completion":{
+				"dynamicRegistration":true,
+				"completionItem":{"snippetSupport":true,"documentationFormat":["markdown","plaintext"],"labelDetailsSupport":true},
+				"completionItemKind":{"valueSet":[1,19]}
+			},
+			"hover":{"contentFormat":["plaintext","markdown"]},
+			"definition":{"linkSupport":true},
+			"documentSymbol":{"hierarchicalDocumentSymbolSupport":true},
+			"rename":{"prepareSupport":true}
+		}
+	}`)
+
+	if !support.Snippets() || !support.CompletionLabelDetails() {
+		t.Fatalf("expected snippet and label details support")
+	}
+
+	if support.CompletionDocumentationFormat() != protocol.MarkupKindMarkdown {
+		t.Fatalf("expected markdown completion documentation")
+	}
+
+	if !support.MarkdownHover() || support.HoverFormat() != protocol.MarkupKindPlainText {
+		t.Fatalf("expected markdown hover support with plaintext preferred")
+	}
+
+	if !support.CompletionItemKind(protocol.CompletionItemKindFolder) || support.CompletionItemKind(protocol.CompletionItemKindMethod) {
+		t.Fatalf("expected announced completion item kinds to replace the defaults")
+	}
+
+	if !support.DefinitionLinks() || !support.HierarchicalDocumentSymbols() || !support.PrepareRename() {
+		t.Fatalf("expected definition links, hierarchical symbols and prepare rename support")
+	}
+
+	if !support.DynamicRegistration(protocol.MethodTextDocumentCompletion) || support.DynamicRegistration(protocol.MethodTextDocumentHover) {
+		t.Fatalf("unexpected dynamic registration support")
+	}
+}
+
+func Test_ClientSupport_WorkspaceWindowAndGeneral(t *testing.T) {
+	support := newClientSupport(t, `{
+		"workspace":{
+			"applyEdit":true,
+			"workspaceEdit":{"documentChanges":true,"resourceOperations":["create","rename"]},
+			"workspaceFolders":true,
+			"configuration":true,
+			"didChangeWatchedFiles":{"dynamicRegistration":true}
+		},
+		"window":{"workDoneProgress":true,"showDocument":{"support":true}},
+		"general":{
+			"positionEncodings":["utf-16","utf-32"],
+			"staleRequestSupport":{"cancel":true,"retryOnContentModified":["textDocument/semanticTokens/full"]}
+		}
+	}`)
+
+	if !support.ApplyEdit() || !support.DocumentChanges() || !support.WorkspaceFolders() || !support.Configuration() {
+		t.Fatalf("expected workspace features to be supported")
+	}
+
+	if !support.ResourceOperation(protocol.ResourceOperationRename) || support.ResourceOperation(protocol.ResourceOperationDelete) {
+		t.Fatalf("unexpected resource operation support")
+	}
+
+	if !support.WorkDoneProgress() || !support.ShowDocument() {
+		t.Fatalf("expected window features to be supported")
+	}
+
+	if !support.RetryOnContentModified("textDocument/semanticTokens/full") || support.RetryOnContentModified(protocol.MethodTextDocumentHover) {
+		t.Fatalf("unexpected retryOnContentModified support")
+	}
+
+	if !support.DynamicRegistration("workspace/didChangeWatchedFiles") {
+		t.Fatalf("expected dynamic registration for didChangeWatchedFiles")
+	}
+
+	if encoding := support.PositionEncoding(); encoding != protocol.PositionEncodingKindUTF32 {
+		t.Fatalf("expected utf-32, got %s", encoding)
+	}
+
+	if encoding := support.PositionEncoding(protocol.PositionEncodingKindUTF16, protocol.PositionEncodingKindUTF32); encoding != protocol.PositionEncodingKindUTF16 {
+		t.Fatalf("expected server preference utf-16, got %s", encoding)
+	}
+
+	if encoding := support.PositionEncoding(protocol.PositionEncodingKindUTF8); encoding != protocol.PositionEncodingKindUTF16 {
+		t.Fatalf("expected fallback to utf-16, got %s", encoding)
+	}
+}
@@ -0,0 +1,238 @@
+package protocol
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseColor parses a CSS color written as a hex color (`#rgb`, `#rgba`,
+// `#rrggbb` or `#rrggbbaa`), an `rgb()`/`rgba()` or an `hsl()`/`hsla()`
+// function. Both the legacy comma separated and the space separated syntax
+// are accepted.
+func ParseColor(s string) (Color, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+
+	if strings.HasPrefix(value, "#") {
+		c, ok := parseHexColor(value[1:])
+		if !ok {
+			return Color{}, fmt.Errorf("invalid hex color %q", s)
+		}
+		return c, nil
+	}
+
+	open := strings.IndexByte(value, '(')
+	if open < 0 || !strings.HasSuffix(value, ")") {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	args := strings.Fields(strings.NewReplacer(",", " ", "/", " ").Replace(value[open+1 : len(value)-1]))
+	if len(args) != 3 && len(args) != 4 {
+		return Color{}, fmt.Errorf("invalid color %q: expected 3 or 4 components", s)
+	}
+
+	var (
+		c  Color
+		ok bool
+	)
+	switch strings.TrimSpace(value[:open]) {
+	case "rgb", "rgba":
+		c, ok = parseRGBColor(args)
+	case "hsl", "hsla":
+		c, ok = parseHSLColor(args)
+	default:
+		return Color{}, fmt.Errorf("invalid color %q: unknown function", s)
+	}
+	if !ok {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	return c, nil
+}
+
+// Hex returns c as a `#rrggbb` CSS color, or `#rrggbbaa` if c is not opaque.
+func (c Color) Hex() string {
+	hex := fmt.Sprintf("#%02x%02x%02x", toByte(c.Red), toByte(c.Green), toByte(c.Blue))
+	if c.Alpha < 1 {
+		hex += fmt.Sprintf("%02x", toByte(c.Alpha))
+	}
+	return hex
+}
+
+// RGB returns c as a CSS `rgb()` color, or `rgba()` if c is not opaque.
+func (c Color) RGB() string {
+	if c.Alpha < 1 {
+		return fmt.Sprintf("rgba(%d, %d, %d, %s)", toByte(c.Red), toByte(c.Green), toByte(c.Blue), formatAlpha(c.Alpha))
+	}
+	return fmt.Sprintf("rgb(%d, %d, %d)", toByte(c.Red), toByte(c.Green), toByte(c.Blue))
+}
+
+// HSL returns c as a CSS `hsl()` color, or `hsla()` if c is not opaque.
+func (c Color) HSL() string {
+	h, s, l := c.hsl()
+	hue := int(math.Round(h)) % 360
+	saturation := int(math.Round(s * 100))
+	lightness := int(math.Round(l * 100))
+
+	if c.Alpha < 1 {
+		return fmt.Sprintf("hsla(%d, %d%%, %d%%, %s)", hue, saturation, lightness, formatAlpha(c.Alpha))
+	}
+	return fmt.Sprintf("hsl(%d, %d%%, %d%%)", hue, saturation, lightness)
+}
+
+// hsl converts c to hue in degrees, saturation and lightness in [0-1].
+func (c Color) hsl() (float64, float64, float64) {
+	r, g, b := clamp01(c.Red), clamp01(c.Green), clamp01(c.Blue)
+	hi := math.Max(r, math.Max(g, b))
+	lo := math.Min(r, math.Min(g, b))
+	l := (hi + lo) / 2
+
+	if hi == lo {
+		return 0, 0, l
+	}
+
+	d := hi - lo
+	s := d / (1 - math.Abs(2*l-1))
+
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+func parseHexColor(hex string) (Color, bool) {
+	switch len(hex) {
+	case 3, 4:
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	case 6, 8:
+	default:
+		return Color{}, false
+	}
+
+	components := [4]float64{1, 1, 1, 1}
+	for i := 0; i < len(hex)/2; i++ {
+		v, err := strconv.ParseUint(hex[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return Color{}, false
+		}
+		components[i] = float64(v) / 255
+	}
+	return Color{Red: components[0], Green: components[1], Blue: components[2], Alpha: components[3]}, true
+}
+
+func parseRGBColor(args []string) (Color, bool) {
+	var components [3]float64
+	for i := range components {
+		v, percent, ok := parseColorNumber(args[i])
+		if !ok {
+			return Color{}, false
+		}
+		if percent {
+			components[i] = clamp01(v / 100)
+		} else {
+			components[i] = clamp01(v / 255)
+		}
+	}
+
+	alpha, ok := parseAlpha(args)
+	if !ok {
+		return Color{}, false
+	}
+	return Color{Red: components[0], Green: components[1], Blue: components[2], Alpha: alpha}, true
+}
+
+func parseHSLColor(args []string) (Color, bool) {
+	hue, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+	if err != nil {
+		return Color{}, false
+	}
+	s, _, ok := parseColorNumber(args[1])
+	if !ok {
+		return Color{}, false
+	}
+	l, _, ok := parseColorNumber(args[2])
+	if !ok {
+		return Color{}, false
+	}
+	alpha, ok := parseAlpha(args)
+	if !ok {
+		return Color{}, false
+	}
+
+	r, g, b := hslToRGB(math.Mod(math.Mod(hue, 360)+360, 360), clamp01(s/100), clamp01(l/100))
+	return Color{Red: r, Green: g, Blue: b, Alpha: alpha}, true
+}
+
+// parseAlpha parses the optional fourth component, defaulting to opaque.
+func parseAlpha(args []string) (float64, bool) {
+	if len(args) < 4 {
+		return 1, true
+	}
+	v, percent, ok := parseColorNumber(args[3])
+	if !ok {
+		return 0, false
+	}
+	if percent {
+		v /= 100
+	}
+	return clamp01(v), true
+}
+
+// parseColorNumber parses a number with an optional percent sign.
+func parseColorNumber(s string) (float64, bool, bool) {
+	percent := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, false, false
+	}
+	return v, percent, true
+}
+
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return r + m, g + m, b + m
+}
+
+func toByte(v float64) uint8 {
+	return uint8(math.Round(clamp01(v) * 255))
+}
+
+func formatAlpha(v float64) string {
+	return strconv.FormatFloat(math.Round(clamp01(v)*100)/100, 'f', -1, 64)
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,137 @@
+package protocol_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func colorsEqual(a, b protocol.Color) bool {
+	const epsilon = 0.002
+	return math.Abs(a.Red-b.Red) < epsilon &&
+		math.Abs(a.Green-b.Green) < epsilon &&
+		math.Abs(a.Blue-b.Blue) < epsilon &&
+		math.Abs(a.Alpha-b.Alpha) < epsilon
+}
+
+func Test_Color_Parse(t *testing.T) {
+	red := protocol.Color{Red: 1, Alpha: 1}
+	tealish := protocol.Color{Red: 20.0 / 255, Green: 184.0 / 255, Blue: 166.0 / 255, Alpha: 1}
+	translucent := protocol.Color{Red: 1, Alpha: 0.5}
+
+	tests := map[string]protocol.Color{
+		"#f00":                      red,
+		"#F00F":                     red,
+		"#ff0000":                   red,
+		"#ff000080":                 {Red: 1, Alpha: 128.0 / 255},
+		"#14b8a6":                   tealish,
+		"rgb(255, 0, 0)":            red,
+		"rgb(100% 0% 0%)":           red,
+		"rgba(255, 0, 0, 0.5)":      translucent,
+		"rgb(255 0 0 / 50%)":        translucent,
+		"rgb(20 184 166)":           tealish,
+		"hsl(0, 100%, 50%)":         red,
+		"hsl(360deg 100% 50%)":      red,
+		"hsla(0, 100%, 50%, 0.5)":   translucent,
+		"hsl(173, 80%, 40%)":        {Red: 0.08, Green: 0.72, Blue: 0.6453, Alpha: 1},
+		"  HSL(120, 100%, 25%)  ":   {Green: 0.5, Alpha: 1},
+		"rgba(300, -20, 128, 1.5)":  {Red: 1, Blue: 128.0 / 255, Alpha: 1},
+		"hsl(240 100% 50% / 0.25)":  {Blue: 1, Alpha: 0.25},
+		"rgb(0.0, 127.5, 255.0)":    {Green: 0.5, Blue: 1, Alpha: 1},
+		"hsl(-120, 100%, 50%)":      {Blue: 1, Alpha: 1},
+		"hsl(180deg, 0%, 100%)":     {Red: 1, Green: 1, Blue: 1, Alpha: 1},
+		"rgba(0, 0, 0, 0%)":         {},
+		"rgb( 255 , 255 , 255 )":    {Red: 1, Green: 1, Blue: 1, Alpha: 1},
+		"hsla(60 100% 50% / 100%)":  {Red: 1, Green: 1, Alpha: 1},
+		"#000":                      {Alpha: 1},
+		"#ffffff":                   {Red: 1, Green: 1, Blue: 1, Alpha: 1},
+		"rgb(51, 102, 153)":         {Red: 0.2, Green: 0.4, Blue: 0.6, Alpha: 1},
+		"hsl(210, 50%, 40%)":        {Red: 0.2, Green: 0.4, Blue: 0.6, Alpha: 1},
+		"rgba(51, 102, 153, 0.75)":  {Red: 0.2, Green: 0.4, Blue: 0.6, Alpha: 0.75},
+		"hsla(210, 50%, 40%, 0.75)": {Red: 0.2, Green: 0.4, Blue: 0.6, Alpha: 0.75},
+	}
+
+	for input, expected := range tests {
+		color, err := protocol.ParseColor(input)
+		if err != nil {
+			t.Fatalf("parse %q failed: %v", input, err)
+		}
+		if !colorsEqual(color, expected) {
+			t.Fatalf("parse %q: expected %+v, got %+v", input, expected, color)
+		}
+	}
+}
+
+func Test_Color_ParseInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"red",
+		"#ff",
+		"#gggggg",
+		"rgb(255, 0)",
+		"rgb(255, 0, 0, 1, 1)",
+		"rgb(a, b, c)",
+		"cmyk(0, 0, 0, 0)",
+		"rgb(255, 0, 0",
+		"hsl(red, 100%, 50%)",
+	} {
+		if _, err := protocol.ParseColor(input); err == nil {
+			t.Fatalf("expected error parsing %q", input)
+		}
+	}
+}
+
+func Test_Color_Format(t *testing.T) {
+	tests := []struct {
+		color protocol.Color
+		hex   string
+		rgb   string
+		hsl   string
+	}{
+		{
+			color: protocol.Color{Red: 1, Alpha: 1},
+			hex:   "#ff0000",
+			rgb:   "rgb(255, 0, 0)",
+			hsl:   "hsl(0, 100%, 50%)",
+		},
+		{
+			color: protocol.Color{Red: 0.2, Green: 0.4, Blue: 0.6, Alpha: 0.75},
+			hex:   "#336699bf",
+			rgb:   "rgba(51, 102, 153, 0.75)",
+			hsl:   "hsla(210, 50%, 40%, 0.75)",
+		},
+		{
+			color: protocol.Color{Red: 0.5, Green: 0.5, Blue: 0.5, Alpha: 1},
+			hex:   "#808080",
+			rgb:   "rgb(128, 128, 128)",
+			hsl:   "hsl(0, 0%, 50%)",
+		},
+	}
+
+	for _, tt := range tests {
+		if hex := tt.color.Hex(); hex != tt.hex {
+			t.Fatalf("expected hex %s, got %s", tt.hex, hex)
+		}
+		if rgb := tt.color.RGB(); rgb != tt.rgb {
+			t.Fatalf("expected rgb %s, got %s", tt.rgb, rgb)
+		}
+		if hsl := tt.color.HSL(); hsl != tt.hsl {
+			t.Fatalf("expected hsl %s, got %s", tt.hsl, hsl)
+		}
+	}
+}
+
+func Test_Color_RoundTrip(t *testing.T) {
+	color := protocol.Color{Red: 20.0 / 255, Green: 184.0 / 255, Blue: 166.0 / 255, Alpha: 1}
+
+	for _, format := range []string{color.Hex(), color.RGB()} {
+		parsed, err := protocol.ParseColor(format)
+		if err != nil {
+			t.Fatalf("parse %q failed: %v", format, err)
+		}
+		if !colorsEqual(parsed, color) {
+			t.Fatalf("round trip of %q: expected %+v, got %+v", format, color, parsed)
+		}
+	}
+}
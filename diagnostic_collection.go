@@ -0,0 +1,297 @@
+package protocol
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DiagnosticCollection holds push model diagnostics per document and per
+// source, and publishes them with `textDocument/publishDiagnostics`.
+//
+// Every producer owns the diagnostics of its source: setting the diagnostics
+// of one source leaves those of other sources untouched, and the published
+// list merges all sources of a document. Changes are debounced so that a
+// burst of updates results in a single notification per document.
+//
+// Once a document is closed, diagnostics set for it are dropped until it is
+// opened again, so that analyzers still running for a closed document do not
+// publish stale diagnostics. Pass `textDocument/didOpen` and
+// `textDocument/didClose` to DidOpen and DidClose for this to work.
+type DiagnosticCollection struct {
+	notify NotifyFunc
+	delay  time.Duration
+
+	// publishMu serializes publishing, so that snapshots of a document are
+	// sent in the order they were taken.
+	publishMu sync.Mutex
+
+	mu        sync.Mutex
+	documents map[DocumentURI]*diagnosticDocument
+	dirty     map[DocumentURI]struct{}
+	timer     *time.Timer
+	closed    bool
+
+	// closedDocuments holds the documents closed since they were last opened.
+	closedDocuments map[DocumentURI]struct{}
+}
+
+type diagnosticDocument struct {
+	sources map[string]diagnosticSource
+}
+
+type diagnosticSource struct {
+	version     *int
+	diagnostics []Diagnostic
+}
+
+// NewDiagnosticCollection creates a collection publishing through notify,
+// once no change happened for delay. A delay of zero or less publishes on
+// every change.
+//
+// Errors of publishes triggered by a change are dropped; call Flush to
+// publish and observe errors.
+func NewDiagnosticCollection(notify NotifyFunc, delay time.Duration) *DiagnosticCollection {
+	return &DiagnosticCollection{
+		notify:    notify,
+		delay:     delay,
+		documents: make(map[DocumentURI]*diagnosticDocument),
+		dirty:     make(map[DocumentURI]struct{}),
+
+		closedDocuments: make(map[DocumentURI]struct{}),
+	}
+}
+
+// Set replaces the diagnostics of source for uri. Diagnostics without a
+// source get source assigned. Diagnostics for a closed document are dropped.
+//
+// version is the document version the diagnostics were computed for, or nil
+// if unknown. A version is only published if all sources of the document
+// were computed for that same version.
+func (c *DiagnosticCollection) Set(uri DocumentURI, source string, version *int, diagnostics []Diagnostic) {
+	items := make([]Diagnostic, len(diagnostics))
+	for i, d := range diagnostics {
+		if d.Source == "" {
+			d.Source = source
+		}
+		items[i] = d
+	}
+
+	c.mu.Lock()
+	if _, closed := c.closedDocuments[uri]; closed {
+		c.mu.Unlock()
+		return
+	}
+	doc, ok := c.documents[uri]
+	if !ok {
+		doc = &diagnosticDocument{sources: make(map[string]diagnosticSource)}
+		c.documents[uri] = doc
+	}
+	entry := diagnosticSource{diagnostics: items}
+	if version != nil {
+		v := *version
+		entry.version = &v
+	}
+	doc.sources[source] = entry
+	c.dirty[uri] = struct{}{}
+	c.mu.Unlock()
+
+	c.changed()
+}
+
+// Clear removes the diagnostics of source for uri.
+func (c *DiagnosticCollection) Clear(uri DocumentURI, source string) {
+	c.mu.Lock()
+	doc, ok := c.documents[uri]
+	if ok {
+		c.removeSource(uri, doc, source)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.changed()
+	}
+}
+
+// ClearSource removes the diagnostics of source for all documents.
+func (c *DiagnosticCollection) ClearSource(source string) {
+	c.mu.Lock()
+	changed := false
+	for uri, doc := range c.documents {
+		if _, ok := doc.sources[source]; ok {
+			c.removeSource(uri, doc, source)
+			changed = true
+		}
+	}
+	c.mu.Unlock()
+
+	if changed {
+		c.changed()
+	}
+}
+
+// DidOpen accepts diagnostics for the opened document again after it was
+// closed.
+func (c *DiagnosticCollection) DidOpen(params DidOpenTextDocumentParams) {
+	uri := DocumentURI(params.TextDocument.URI)
+
+	c.mu.Lock()
+	delete(c.closedDocuments, uri)
+	c.mu.Unlock()
+}
+
+// DidClose removes all diagnostics of the closed document, publishing an
+// empty list so the client clears them. Diagnostics set for the document
+// are dropped until DidOpen is called for it.
+func (c *DiagnosticCollection) DidClose(params DidCloseTextDocumentParams) {
+	uri := DocumentURI(params.TextDocument.URI)
+
+	c.mu.Lock()
+	c.closedDocuments[uri] = struct{}{}
+	_, ok := c.documents[uri]
+	if ok {
+		delete(c.documents, uri)
+		c.dirty[uri] = struct{}{}
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.changed()
+	}
+}
+
+// Get returns the merged diagnostics of uri, ordered by source.
+func (c *DiagnosticCollection) Get(uri DocumentURI) []Diagnostic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.params(uri).Diagnostics
+}
+
+// Documents returns the number of documents holding diagnostics.
+func (c *DiagnosticCollection) Documents() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.documents)
+}
+
+// Flush publishes all pending changes right away. If publishing fails, the
+// documents not published yet stay pending for the next Flush.
+func (c *DiagnosticCollection) Flush(ctx context.Context) error {
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+
+	c.mu.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	pending := make([]PublishDiagnosticsParams, 0, len(c.dirty))
+	for uri := range c.dirty {
+		pending = append(pending, c.params(uri))
+	}
+	c.dirty = make(map[DocumentURI]struct{})
+	c.mu.Unlock()
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].URI < pending[j].URI
+	})
+
+	for i, params := range pending {
+		if err := c.notify(ctx, MethodTextDocumentPublishDiagnostics, params); err != nil {
+			c.mu.Lock()
+			for _, unsent := range pending[i:] {
+				c.dirty[unsent.URI] = struct{}{}
+			}
+			c.mu.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops any pending debounced publish. Changes made after Close are
+// no longer published automatically; Flush still publishes them.
+func (c *DiagnosticCollection) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+}
+
+// removeSource removes the diagnostics of source from doc, dropping doc
+// once it has no sources left. The caller must hold c.mu.
+func (c *DiagnosticCollection) removeSource(uri DocumentURI, doc *diagnosticDocument, source string) {
+	delete(doc.sources, source)
+	if len(doc.sources) == 0 {
+		delete(c.documents, uri)
+	}
+	c.dirty[uri] = struct{}{}
+}
+
+// changed publishes or schedules publishing of the pending changes.
+func (c *DiagnosticCollection) changed() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	if c.delay <= 0 {
+		c.mu.Unlock()
+		_ = c.Flush(context.Background())
+		return
+	}
+	defer c.mu.Unlock()
+
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.delay, func() {
+			c.mu.Lock()
+			closed := c.closed
+			c.mu.Unlock()
+
+			if !closed {
+				_ = c.Flush(context.Background())
+			}
+		})
+		return
+	}
+	c.timer.Reset(c.delay)
+}
+
+// params builds the notification params for uri. The caller must hold c.mu.
+func (c *DiagnosticCollection) params(uri DocumentURI) PublishDiagnosticsParams {
+	params := PublishDiagnosticsParams{URI: uri, Diagnostics: []Diagnostic{}}
+
+	doc, ok := c.documents[uri]
+	if !ok {
+		return params
+	}
+
+	sources := make([]string, 0, len(doc.sources))
+	for source := range doc.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var version *int
+	for i, source := range sources {
+		entry := doc.sources[source]
+		params.Diagnostics = append(params.Diagnostics, entry.diagnostics...)
+
+		switch {
+		case i == 0:
+			version = entry.version
+		case version != nil && (entry.version == nil || *entry.version != *version):
+			version = nil
+		}
+	}
+	if version != nil {
+		v := *version
+		params.Version = &v
+	}
+	return params
+}
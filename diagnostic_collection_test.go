@@ -0,0 +1,283 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func newPublishRecorder(t *testing.T) (protocol.NotifyFunc, <-chan protocol.PublishDiagnosticsParams) {
+	t.Helper()
+
+	published := make(chan protocol.PublishDiagnosticsParams, 16)
+	notify := func(ctx context.Context, method string, params any) error {
+		if method != protocol.MethodTextDocumentPublishDiagnostics {
+			t.Errorf("unexpected method %q", method)
+		}
+		published <- params.(protocol.PublishDiagnosticsParams)
+		return nil
+	}
+	return notify, published
+}
+
+func diagnostic(line uint32, message string) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: line},
+			End:   protocol.Position{Line: line, Character: 1},
+		},
+		Message: message,
+	}
+}
+
+func Test_DiagnosticCollection_MergesSources(t *testing.T) {
+	notify, published := newPublishRecorder(t)
+	collection := protocol.NewDiagnosticCollection(notify, 0)
+	uri := protocol.DocumentURI("file:///app/User.php")
+	version := 2
+
+	collection.Set(uri, "phpstan", &version, []protocol.Diagnostic{diagnostic(1, "undefined variable")})
+	collection.Set(uri, "blade", nil, []protocol.Diagnostic{diagnostic(4, "unknown directive")})
+	collection.Set(uri, "phpstan", nil, []protocol.Diagnostic{diagnostic(2, "unused import")})
+
+	if len(published) != 3 {
+		t.Fatalf("expected 3 publishes, got %d", len(published))
+	}
+
+	var last protocol.PublishDiagnosticsParams
+	for len(published) > 0 {
+		last = <-published
+	}
+
+	// phpstan replaced its versioned diagnostics with unversioned ones, so
+	// version 2 no longer applies to the published diagnostics.
+	if last.URI != uri || last.Version != nil {
+		t.Fatalf("unexpected params: %+v", last)
+	}
+	if len(last.Diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %+v", last.Diagnostics)
+	}
+	if last.Diagnostics[0].Source != "blade" || last.Diagnostics[1].Source != "phpstan" || last.Diagnostics[1].Message != "unused import" {
+		t.Fatalf("unexpected merged diagnostics: %+v", last.Diagnostics)
+	}
+
+	collection.Clear(uri, "phpstan")
+	if got := collection.Get(uri); len(got) != 1 || got[0].Source != "blade" {
+		t.Fatalf("expected only blade diagnostics, got %+v", got)
+	}
+}
+
+func Test_DiagnosticCollection_ClearSource(t *testing.T) {
+	notify, published := newPublishRecorder(t)
+	collection := protocol.NewDiagnosticCollection(notify, time.Hour)
+	defer collection.Close()
+
+	collection.Set("file:///a.php", "phpstan", nil, []protocol.Diagnostic{diagnostic(0, "a")})
+	collection.Set("file:///b.php", "phpstan", nil, []protocol.Diagnostic{diagnostic(0, "b")})
+	collection.Set("file:///b.php", "pint", nil, []protocol.Diagnostic{diagnostic(0, "style")})
+	collection.ClearSource("phpstan")
+
+	if err := collection.Flush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	if len(published) != 2 {
+		t.Fatalf("expected 2 publishes, got %d", len(published))
+	}
+	a, b := <-published, <-published
+	if a.URI != "file:///a.php" || len(a.Diagnostics) != 0 {
+		t.Fatalf("expected a.php to be cleared, got %+v", a)
+	}
+	if b.URI != "file:///b.php" || len(b.Diagnostics) != 1 || b.Diagnostics[0].Source != "pint" {
+		t.Fatalf("expected only pint diagnostics for b.php, got %+v", b)
+	}
+}
+
+func Test_DiagnosticCollection_DidClosePublishesEmpty(t *testing.T) {
+	notify, published := newPublishRecorder(t)
+	collection := protocol.NewDiagnosticCollection(notify, 0)
+	uri := protocol.DocumentURI("file:///a.php")
+
+	collection.Set(uri, "phpstan", nil, []protocol.Diagnostic{diagnostic(0, "a")})
+	<-published
+
+	collection.DidClose(protocol.DidCloseTextDocumentParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: string(uri)},
+	})
+
+	params := <-published
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != `{"uri":"file:///a.php","diagnostics":[]}` {
+		t.Fatalf("unexpected payload: %s", data)
+	}
+
+	collection.DidClose(protocol.DidCloseTextDocumentParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: string(uri)},
+	})
+	if len(published) != 0 {
+		t.Fatalf("expected no publish for an unknown document")
+	}
+}
+
+func Test_DiagnosticCollection_Debounces(t *testing.T) {
+	notify, published := newPublishRecorder(t)
+	collection := protocol.NewDiagnosticCollection(notify, 20*time.Millisecond)
+	defer collection.Close()
+	uri := protocol.DocumentURI("file:///a.php")
+
+	for i := 0; i < 5; i++ {
+		collection.Set(uri, "phpstan", nil, []protocol.Diagnostic{diagnostic(uint32(i), "a")})
+	}
+
+	select {
+	case params := <-published:
+		if len(params.Diagnostics) != 1 || params.Diagnostics[0].Range.Start.Line != 4 {
+			t.Fatalf("expected the last diagnostics, got %+v", params.Diagnostics)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for publish")
+	}
+
+	select {
+	case params := <-published:
+		t.Fatalf("expected a single publish, got another: %+v", params)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func Test_DiagnosticCollection_FlushKeepsUnsentOnError(t *testing.T) {
+	failure := errors.New("write failed")
+	fail := true
+	var sent []protocol.DocumentURI
+	notify := func(ctx context.Context, method string, params any) error {
+		if fail {
+			return failure
+		}
+		sent = append(sent, params.(protocol.PublishDiagnosticsParams).URI)
+		return nil
+	}
+
+	collection := protocol.NewDiagnosticCollection(notify, time.Hour)
+	defer collection.Close()
+
+	collection.Set("file:///a.php", "phpstan", nil, []protocol.Diagnostic{diagnostic(1, "a")})
+	collection.Set("file:///b.php", "phpstan", nil, []protocol.Diagnostic{diagnostic(1, "b")})
+
+	if err := collection.Flush(context.Background()); !errors.Is(err, failure) {
+		t.Fatalf("expected publish error, got %v", err)
+	}
+
+	fail = false
+	if err := collection.Flush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if len(sent) != 2 || sent[0] != "file:///a.php" || sent[1] != "file:///b.php" {
+		t.Fatalf("expected both documents to be published after the failure, got %v", sent)
+	}
+}
+
+func Test_DiagnosticCollection_PublishesCommonVersion(t *testing.T) {
+	notify, published := newPublishRecorder(t)
+	collection := protocol.NewDiagnosticCollection(notify, 0)
+	uri := protocol.DocumentURI("file:///app/User.php")
+	v3, v4 := 3, 4
+
+	collection.Set(uri, "phpstan", &v3, []protocol.Diagnostic{diagnostic(1, "a")})
+	collection.Set(uri, "blade", &v4, []protocol.Diagnostic{diagnostic(2, "b")})
+	collection.Set(uri, "phpstan", &v4, []protocol.Diagnostic{diagnostic(3, "c")})
+
+	var versions []*int
+	for len(published) > 0 {
+		versions = append(versions, (<-published).Version)
+	}
+
+	if len(versions) != 3 || versions[0] == nil || *versions[0] != 3 || versions[1] != nil || versions[2] == nil || *versions[2] != 4 {
+		t.Fatalf("expected versions 3, none, 4, got %v", versions)
+	}
+}
+
+func Test_DiagnosticCollection_CloseIsFinal(t *testing.T) {
+	notify, published := newPublishRecorder(t)
+	collection := protocol.NewDiagnosticCollection(notify, 10*time.Millisecond)
+	uri := protocol.DocumentURI("file:///a.php")
+
+	collection.Set(uri, "phpstan", nil, []protocol.Diagnostic{diagnostic(1, "a")})
+	collection.Close()
+	collection.Set(uri, "phpstan", nil, []protocol.Diagnostic{diagnostic(2, "b")})
+
+	select {
+	case params := <-published:
+		t.Fatalf("expected no publish after Close, got %+v", params)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := collection.Flush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if params := <-published; len(params.Diagnostics) != 1 || params.Diagnostics[0].Message != "b" {
+		t.Fatalf("expected Flush to publish the latest diagnostics, got %+v", params)
+	}
+}
+
+func Test_DiagnosticCollection_DropsDiagnosticsForClosedDocuments(t *testing.T) {
+	notify, published := newPublishRecorder(t)
+	collection := protocol.NewDiagnosticCollection(notify, 0)
+	uri := protocol.DocumentURI("file:///a.php")
+
+	collection.Set(uri, "phpstan", nil, []protocol.Diagnostic{diagnostic(0, "a")})
+	collection.DidClose(protocol.DidCloseTextDocumentParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: string(uri)},
+	})
+	for len(published) > 0 {
+		<-published
+	}
+
+	// An analyzer still running for the closed document finishes.
+	collection.Set(uri, "blade", nil, []protocol.Diagnostic{diagnostic(1, "stale")})
+	if len(published) != 0 || collection.Documents() != 0 {
+		t.Fatalf("expected diagnostics for a closed document to be dropped")
+	}
+
+	collection.DidOpen(protocol.DidOpenTextDocumentParams{
+		TextDocument: protocol.TextDocumentItem{URI: string(uri), LanguageID: "php", Version: 1, Text: ""},
+	})
+	collection.Set(uri, "blade", nil, []protocol.Diagnostic{diagnostic(1, "fresh")})
+	if params := <-published; len(params.Diagnostics) != 1 || params.Diagnostics[0].Message != "fresh" {
+		t.Fatalf("expected diagnostics after reopening, got %+v", params)
+	}
+}
+
+func Test_DiagnosticCollection_ClearDropsEmptyDocuments(t *testing.T) {
+	notify, published := newPublishRecorder(t)
+	collection := protocol.NewDiagnosticCollection(notify, 0)
+
+	collection.Set("file:///a.php", "phpstan", nil, []protocol.Diagnostic{diagnostic(0, "a")})
+	collection.Set("file:///a.php", "blade", nil, []protocol.Diagnostic{diagnostic(1, "b")})
+	collection.Set("file:///b.php", "phpstan", nil, []protocol.Diagnostic{diagnostic(0, "c")})
+
+	collection.Clear("file:///a.php", "phpstan")
+	if n := collection.Documents(); n != 2 {
+		t.Fatalf("expected 2 documents, got %d", n)
+	}
+
+	collection.Clear("file:///a.php", "blade")
+	collection.ClearSource("phpstan")
+	if n := collection.Documents(); n != 0 {
+		t.Fatalf("expected documents without sources to be dropped, got %d", n)
+	}
+
+	var last protocol.PublishDiagnosticsParams
+	for len(published) > 0 {
+		last = <-published
+	}
+	if last.URI != "file:///b.php" || len(last.Diagnostics) != 0 {
+		t.Fatalf("expected an empty publish for the cleared document, got %+v", last)
+	}
+}
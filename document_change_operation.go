@@ -1,10 +1,71 @@
 package protocol
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 // DocumentChangeOperation - represents any valid document change operation.
+//
+// Implemented by TextDocumentEdit, CreateFile, RenameFile and DeleteFile.
 type DocumentChangeOperation interface {
 	isDocumentChangeOperation()
 }
 
+func (TextDocumentEdit) isDocumentChangeOperation() {}
+
+// unmarshalDocumentChangeOperation decodes a single document change operation,
+// using the `kind` property to pick the concrete type. An absent kind
+// denotes a TextDocumentEdit, which must then have a `textDocument`.
+func unmarshalDocumentChangeOperation(data []byte) (DocumentChangeOperation, error) {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil, errors.New("invalid document change operation: null")
+	}
+
+	var temp struct {
+		Kind         string          `json:"kind"`
+		TextDocument json.RawMessage `json:"textDocument"`
+	}
+
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return nil, err
+	}
+
+	switch ResourceOperationKind(temp.Kind) {
+	case "":
+		if temp.TextDocument == nil || string(temp.TextDocument) == "null" {
+			return nil, errors.New("invalid document change operation: neither kind nor textDocument set")
+		}
+		var edit TextDocumentEdit
+		if err := json.Unmarshal(data, &edit); err != nil {
+			return nil, err
+		}
+		return edit, nil
+	case ResourceOperationCreate:
+		var create CreateFile
+		if err := json.Unmarshal(data, &create); err != nil {
+			return nil, err
+		}
+		return create, nil
+	case ResourceOperationRename:
+		var rename RenameFile
+		if err := json.Unmarshal(data, &rename); err != nil {
+			return nil, err
+		}
+		return rename, nil
+	case ResourceOperationDelete:
+		var del DeleteFile
+		if err := json.Unmarshal(data, &del); err != nil {
+			return nil, err
+		}
+		return del, nil
+	}
+
+	return nil, fmt.Errorf("unknown document change operation kind: %s", temp.Kind)
+}
+
 // ResourceOperation - A generic resource operation.
 type ResourceOperation struct {
 	// resource operation kind
@@ -24,6 +85,12 @@ type CreateFile struct {
 
 func (CreateFile) isDocumentChangeOperation() {}
 
+func (c CreateFile) MarshalJSON() ([]byte, error) {
+	type createFile CreateFile
+	c.Kind = string(ResourceOperationCreate)
+	return json.Marshal(createFile(c))
+}
+
 // CreateFileOptions - Options to create a file.
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#createFileOptions
 type CreateFileOptions struct {
@@ -55,6 +122,12 @@ type RenameFileOptions struct {
 
 func (RenameFile) isDocumentChangeOperation() {}
 
+func (r RenameFile) MarshalJSON() ([]byte, error) {
+	type renameFile RenameFile
+	r.Kind = string(ResourceOperationRename)
+	return json.Marshal(renameFile(r))
+}
+
 // DeleteFile operation.
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#deleteFile
 type DeleteFile struct {
@@ -65,6 +138,12 @@ type DeleteFile struct {
 
 func (DeleteFile) isDocumentChangeOperation() {}
 
+func (d DeleteFile) MarshalJSON() ([]byte, error) {
+	type deleteFile DeleteFile
+	d.Kind = string(ResourceOperationDelete)
+	return json.Marshal(deleteFile(d))
+}
+
 // DeleteFileOptions - Options to delete a file.
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#deleteFileOptions
 type DeleteFileOptions struct {
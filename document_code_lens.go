@@ -0,0 +1,90 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const (
+	// MethodTextDocumentCodeLens method name of "textDocument/codeLens".
+	MethodTextDocumentCodeLens = "textDocument/codeLens"
+
+	// MethodCodeLensResolve method name of "codeLens/resolve".
+	MethodCodeLensResolve = "codeLens/resolve"
+
+	// MethodWorkspaceCodeLensRefresh method name of "workspace/codeLens/refresh".
+	MethodWorkspaceCodeLensRefresh = "workspace/codeLens/refresh"
+)
+
+// CodeLensParams defines the parameters for a textDocument/codeLens request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#codeLensParams
+type CodeLensParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// The document to request code lens for.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// CodeLens - A code lens represents a command that should be shown along with
+// source text, like the number of references, a way to run tests, etc.
+//
+// A code lens is _unresolved_ when no command is associated to it. For
+// performance reasons the creation of a code lens and resolving should be done
+// in two stages.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#codeLens
+type CodeLens struct {
+	// The range in which this code lens is valid. Should only span a single
+	// line.
+	Range Range `json:"range"`
+
+	// The command this code lens represents.
+	Command *Command `json:"command,omitempty"`
+
+	// A data entry field that is preserved on a code lens item between
+	// a code lens and a code lens resolve request.
+	Data LSPAny `json:"data,omitempty"`
+}
+
+// CodeLensResolver returns a `codeLens/resolve` handler that decodes the data
+// of an unresolved code lens into D and lets fn compute its command.
+//
+// Lenses that already carry a command are returned unchanged. The data is
+// kept on the resolved lens.
+func CodeLensResolver[D any](fn func(ctx context.Context, lens CodeLens, data D) (*Command, error)) func(ctx context.Context, lens CodeLens) (CodeLens, error) {
+	return func(ctx context.Context, lens CodeLens) (CodeLens, error) {
+		if lens.Command != nil {
+			return lens, nil
+		}
+
+		data, err := decodeResolveData[D](lens.Data)
+		if err != nil {
+			return lens, NewResponseError(RPCInvalidParams, "invalid code lens data: %s", err.Error())
+		}
+
+		command, err := fn(ctx, lens, data)
+		if err != nil {
+			return lens, err
+		}
+		lens.Command = command
+		return lens, nil
+	}
+}
+
+// decodeResolveData decodes the data field of an item sent back in a resolve
+// request into D. A nil data decodes to the zero value.
+func decodeResolveData[D any](value LSPAny) (D, error) {
+	var data D
+	if value == nil {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return data, err
+	}
+	err = json.Unmarshal(raw, &data)
+	return data, err
+}
@@ -0,0 +1,125 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+type usagesLensData struct {
+	Class  string `json:"class"`
+	Method string `json:"method"`
+}
+
+func Test_CodeLens_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.CodeLensParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///app/Http/Controllers/UserController.php"}}`), &params); err != nil {
+		t.Fatalf("unmarshal CodeLensParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///app/Http/Controllers/UserController.php" {
+		t.Fatalf("unexpected CodeLensParams: %+v", params)
+	}
+}
+
+func Test_CodeLens_MarshalUnresolved(t *testing.T) {
+	lens := protocol.CodeLens{
+		Range: protocol.Range{Start: protocol.Position{Line: 12, Character: 4}, End: protocol.Position{Line: 12, Character: 25}},
+		Data:  usagesLensData{Class: "App\\Http\\Controllers\\UserController", Method: "index"},
+	}
+
+	data, err := json.Marshal(lens)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	expected := `{"range":{"start":{"line":12,"character":4},"end":{"line":12,"character":25}},"data":{"class":"App\\Http\\Controllers\\UserController","method":"index"}}`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
+
+func Test_CodeLens_ResolverRoundTripsData(t *testing.T) {
+	router := protocol.NewRouter()
+	router.HandleCodeLensResolve(protocol.CodeLensResolver(func(ctx context.Context, lens protocol.CodeLens, data usagesLensData) (*protocol.Command, error) {
+		if data.Class != "App\\Http\\Controllers\\UserController" || data.Method != "index" {
+			t.Fatalf("unexpected lens data: %+v", data)
+		}
+		return &protocol.Command{Title: "3 usages", Command: "laravel.showUsages"}, nil
+	}))
+
+	result, err := router.Handle(context.Background(), newRequest(1, protocol.MethodCodeLensResolve,
+		`{"range":{"start":{"line":12,"character":4},"end":{"line":12,"character":25}},"data":{"class":"App\\Http\\Controllers\\UserController","method":"index"}}`))
+	if err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+
+	expected := `{"range":{"start":{"line":12,"character":4},"end":{"line":12,"character":25}},"command":{"title":"3 usages","command":"laravel.showUsages"},"data":{"class":"App\\Http\\Controllers\\UserController","method":"index"}}`
+	if raw := result.(json.RawMessage); string(raw) != expected {
+		t.Fatalf("expected %s, got %s", expected, raw)
+	}
+}
+
+func Test_CodeLens_ResolverKeepsResolvedLens(t *testing.T) {
+	resolve := protocol.CodeLensResolver(func(ctx context.Context, lens protocol.CodeLens, data usagesLensData) (*protocol.Command, error) {
+		t.Fatalf("resolver called for resolved lens")
+		return nil, nil
+	})
+
+	lens := protocol.CodeLens{Command: &protocol.Command{Title: "run test", Command: "laravel.runTest"}}
+	resolved, err := resolve(context.Background(), lens)
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if resolved.Command != lens.Command {
+		t.Fatalf("expected command to be kept, got %+v", resolved.Command)
+	}
+}
+
+func Test_CodeLens_ResolverInvalidData(t *testing.T) {
+	resolve := protocol.CodeLensResolver(func(ctx context.Context, lens protocol.CodeLens, data usagesLensData) (*protocol.Command, error) {
+		t.Fatalf("resolver called with invalid data")
+		return nil, nil
+	})
+
+	_, err := resolve(context.Background(), protocol.CodeLens{Data: "not an object"})
+	expectErrorCode(t, err, protocol.RPCInvalidParams)
+}
+
+func Test_CodeLens_ResolverPassesErrors(t *testing.T) {
+	failure := errors.New("index not ready")
+	resolve := protocol.CodeLensResolver(func(ctx context.Context, lens protocol.CodeLens, data usagesLensData) (*protocol.Command, error) {
+		return nil, failure
+	})
+
+	if _, err := resolve(context.Background(), protocol.CodeLens{}); !errors.Is(err, failure) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+}
+
+func Test_CodeLens_Refresh(t *testing.T) {
+	router := protocol.NewRouter()
+	called := false
+	router.HandleCodeLensRefresh(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	result, err := router.Handle(context.Background(), newRequest(1, protocol.MethodWorkspaceCodeLensRefresh, ""))
+	if err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+	if !called || string(result.(json.RawMessage)) != "null" {
+		t.Fatalf("expected refresh handler to be called with null result, got %v", result)
+	}
+
+	support := newClientSupport(t, `{"workspace":{"codeLens":{"refreshSupport":true}}}`)
+	if !support.CodeLensRefresh() {
+		t.Fatalf("expected code lens refresh support")
+	}
+	if newClientSupport(t, `{}`).CodeLensRefresh() {
+		t.Fatalf("expected no code lens refresh support by default")
+	}
+}
@@ -0,0 +1,110 @@
+package protocol
+
+const (
+	// MethodTextDocumentDocumentColor method name of "textDocument/documentColor".
+	MethodTextDocumentDocumentColor = "textDocument/documentColor"
+
+	// MethodTextDocumentColorPresentation method name of "textDocument/colorPresentation".
+	MethodTextDocumentColorPresentation = "textDocument/colorPresentation"
+)
+
+// DocumentColorParams defines the parameters for a textDocument/documentColor request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentColorParams
+//
+// @since 3.6.0
+type DocumentColorParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// The text document.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// ColorInformation - A color found in a document.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#colorInformation
+//
+// @since 3.6.0
+type ColorInformation struct {
+	// The range in the document where this color appears.
+	Range Range `json:"range"`
+
+	// The actual color value for this color range.
+	Color Color `json:"color"`
+}
+
+// Color - Represents a color in RGBA space. Every component is in the range
+// [0-1].
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#color
+//
+// @since 3.6.0
+type Color struct {
+	// The red component of this color in the range [0-1].
+	Red float64 `json:"red"`
+
+	// The green component of this color in the range [0-1].
+	Green float64 `json:"green"`
+
+	// The blue component of this color in the range [0-1].
+	Blue float64 `json:"blue"`
+
+	// The alpha component of this color in the range [0-1].
+	Alpha float64 `json:"alpha"`
+}
+
+// ColorPresentationParams defines the parameters for a textDocument/colorPresentation request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#colorPresentationParams
+//
+// @since 3.6.0
+type ColorPresentationParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// The text document.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// The color information to request presentations for.
+	Color Color `json:"color"`
+
+	// The range where the color would be inserted. Serves as a context.
+	Range Range `json:"range"`
+}
+
+// ColorPresentation - A way to present a color as text.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#colorPresentation
+//
+// @since 3.6.0
+type ColorPresentation struct {
+	// The label of this color presentation. It will be shown on the color
+	// picker header. By default this is also the text that is inserted when
+	// selecting this color presentation.
+	Label string `json:"label"`
+
+	// An edit which is applied to a document when selecting this
+	// presentation for the color. When omitted the label is used.
+	TextEdit *TextEdit `json:"textEdit,omitempty"`
+
+	// An optional array of additional text edits that are applied when
+	// selecting this color presentation. Edits must not overlap with the main
+	// edit nor with themselves.
+	AdditionalTextEdits []TextEdit `json:"additionalTextEdits,omitempty"`
+}
+
+// ColorPresentations returns the hex, rgb() and hsl() presentations of c,
+// each replacing rng with its label.
+func ColorPresentations(c Color, rng Range) []ColorPresentation {
+	labels := []string{c.Hex(), c.RGB(), c.HSL()}
+
+	presentations := make([]ColorPresentation, len(labels))
+	for i, label := range labels {
+		presentations[i] = ColorPresentation{
+			Label:    label,
+			TextEdit: &TextEdit{Range: rng, NewText: label},
+		}
+	}
+	return presentations
+}
@@ -0,0 +1,66 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentColor_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.DocumentColorParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///resources/views/welcome.blade.php"}}`), &params); err != nil {
+		t.Fatalf("unmarshal DocumentColorParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///resources/views/welcome.blade.php" {
+		t.Fatalf("unexpected DocumentColorParams: %+v", params)
+	}
+}
+
+func Test_DocumentColor_ColorInformationMarshalJSON(t *testing.T) {
+	info := protocol.ColorInformation{
+		Range: protocol.Range{Start: protocol.Position{Line: 3, Character: 18}, End: protocol.Position{Line: 3, Character: 25}},
+		Color: protocol.Color{Red: 1, Green: 0.5, Blue: 0, Alpha: 1},
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	expected := `{"range":{"start":{"line":3,"character":18},"end":{"line":3,"character":25}},"color":{"red":1,"green":0.5,"blue":0,"alpha":1}}`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
+
+func Test_DocumentColor_ColorPresentationParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.ColorPresentationParams
+	data := []byte(`{
+		"textDocument": {"uri": "file:///resources/views/welcome.blade.php"},
+		"color": {"red": 0.2, "green": 0.4, "blue": 0.6, "alpha": 1},
+		"range": {"start": {"line": 3, "character": 18}, "end": {"line": 3, "character": 25}}
+	}`)
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatalf("unmarshal ColorPresentationParams failed: %v", err)
+	}
+	if params.Color.Blue != 0.6 || params.Range.End.Character != 25 {
+		t.Fatalf("unexpected ColorPresentationParams: %+v", params)
+	}
+}
+
+func Test_DocumentColor_ColorPresentations(t *testing.T) {
+	rng := protocol.Range{Start: protocol.Position{Line: 3, Character: 18}, End: protocol.Position{Line: 3, Character: 25}}
+	presentations := protocol.ColorPresentations(protocol.Color{Red: 0.2, Green: 0.4, Blue: 0.6, Alpha: 1}, rng)
+
+	expected := []string{"#336699", "rgb(51, 102, 153)", "hsl(210, 50%, 40%)"}
+	if len(presentations) != len(expected) {
+		t.Fatalf("expected %d presentations, got %d", len(expected), len(presentations))
+	}
+	for i, label := range expected {
+		p := presentations[i]
+		if p.Label != label || p.TextEdit == nil || p.TextEdit.NewText != label || p.TextEdit.Range != rng {
+			t.Fatalf("unexpected presentation %d: %+v", i, p)
+		}
+	}
+}
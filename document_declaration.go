@@ -0,0 +1,22 @@
+package protocol
+
+const (
+	// MethodTextDocumentDeclaration method name of "textDocument/declaration".
+	MethodTextDocumentDeclaration = "textDocument/declaration"
+)
+
+// DeclarationParams defines the parameters for a textDocument/declaration request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#declarationParams
+//
+// @since 3.14.0
+type DeclarationParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocumentPositionParams
+}
+
+// DeclarationResponse represents the result of a textDocument/declaration request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_declaration
+type DeclarationResponse = LocationResult
@@ -0,0 +1,21 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentDeclaration_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.DeclarationParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///app/Http/Kernel.php"},"position":{"line":3,"character":8},"partialResultToken":"decl"}`), &params); err != nil {
+		t.Fatalf("unmarshal DeclarationParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///app/Http/Kernel.php" || params.Position.Line != 3 {
+		t.Fatalf("unexpected DeclarationParams: %+v", params)
+	}
+	if params.PartialResultToken == nil {
+		t.Fatalf("expected partialResultToken to be set")
+	}
+}
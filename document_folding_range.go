@@ -0,0 +1,141 @@
+package protocol
+
+import "sort"
+
+const (
+	// MethodTextDocumentFoldingRange method name of "textDocument/foldingRange".
+	MethodTextDocumentFoldingRange = "textDocument/foldingRange"
+
+	// MethodWorkspaceFoldingRangeRefresh method name of "workspace/foldingRange/refresh".
+	MethodWorkspaceFoldingRangeRefresh = "workspace/foldingRange/refresh"
+)
+
+// FoldingRangeParams defines the parameters for a textDocument/foldingRange request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#foldingRangeParams
+//
+// @since 3.10.0
+type FoldingRangeParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// The text document.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// FoldingRangeKind - A set of predefined range kinds.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#foldingRangeKind
+type FoldingRangeKind string
+
+const (
+	// FoldingRangeKindComment - Folding range for a comment.
+	FoldingRangeKindComment FoldingRangeKind = "comment"
+
+	// FoldingRangeKindImports - Folding range for imports or includes.
+	FoldingRangeKindImports FoldingRangeKind = "imports"
+
+	// FoldingRangeKindRegion - Folding range for a region (e.g. `#region`).
+	FoldingRangeKindRegion FoldingRangeKind = "region"
+)
+
+// FoldingRange - Represents a folding range. To be valid, start and end line
+// must be bigger than zero and smaller than the number of lines in the
+// document. Clients are free to ignore invalid ranges.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#foldingRange
+//
+// @since 3.10.0
+type FoldingRange struct {
+	// The zero-based start line of the range to fold. The folded area starts
+	// after the line's last character. To be valid, the end must be zero or
+	// larger and smaller than the number of lines in the document.
+	StartLine uint32 `json:"startLine"`
+
+	// The zero-based character offset from where the folded range starts. If
+	// not defined, defaults to the length of the start line.
+	StartCharacter *uint32 `json:"startCharacter,omitempty"`
+
+	// The zero-based end line of the range to fold. The folded area ends with
+	// the line's last character. To be valid, the end must be zero or larger
+	// and smaller than the number of lines in the document.
+	EndLine uint32 `json:"endLine"`
+
+	// The zero-based character offset before the folded range ends. If not
+	// defined, defaults to the length of the end line.
+	EndCharacter *uint32 `json:"endCharacter,omitempty"`
+
+	// Describes the kind of the folding range such as `comment` or `region`.
+	// The kind is used to categorize folding ranges and used by commands like
+	// 'Fold all comments'.
+	Kind FoldingRangeKind `json:"kind,omitempty"`
+
+	// The text that the client should show when the specified range is
+	// collapsed. If not defined or not supported by the client, a default
+	// will be chosen by the client.
+	//
+	// @since 3.17.0
+	CollapsedText string `json:"collapsedText,omitempty"`
+}
+
+// FilterFoldingRanges adapts ranges to the folding range capabilities of the
+// client.
+//
+// Ranges ending before they start are dropped. If the client only folds
+// whole lines, character offsets are removed and ranges spanning a single
+// line are dropped. Collapsed text is removed if the client does not support
+// it. If the client announced a range limit, only the ranges starting first
+// in the document are kept.
+//
+// The result keeps the order of ranges, regardless of the range limit. The
+// input slice is not modified.
+func FilterFoldingRanges(support ClientSupport, ranges []FoldingRange) []FoldingRange {
+	caps := support.textDocument().FoldingRange
+	if caps == nil {
+		caps = &FoldingRangeClientCapabilities{}
+	}
+	collapsedText := caps.FoldingRange != nil && caps.FoldingRange.CollapsedText
+
+	result := make([]FoldingRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r.EndLine < r.StartLine {
+			continue
+		}
+		if caps.LineFoldingOnly {
+			if r.EndLine == r.StartLine {
+				continue
+			}
+			r.StartCharacter = nil
+			r.EndCharacter = nil
+		}
+		if !collapsedText {
+			r.CollapsedText = ""
+		}
+		result = append(result, r)
+	}
+
+	if caps.RangeLimit == 0 || uint32(len(result)) <= caps.RangeLimit {
+		return result
+	}
+
+	order := make([]int, len(result))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return result[order[i]].StartLine < result[order[j]].StartLine
+	})
+
+	keep := make([]bool, len(result))
+	for _, i := range order[:caps.RangeLimit] {
+		keep[i] = true
+	}
+
+	limited := make([]FoldingRange, 0, caps.RangeLimit)
+	for i, r := range result {
+		if keep[i] {
+			limited = append(limited, r)
+		}
+	}
+	return limited
+}
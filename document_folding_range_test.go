@@ -0,0 +1,124 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+func Test_FoldingRange_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.FoldingRangeParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///resources/views/users/index.blade.php"}}`), &params); err != nil {
+		t.Fatalf("unmarshal FoldingRangeParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///resources/views/users/index.blade.php" {
+		t.Fatalf("unexpected FoldingRangeParams: %+v", params)
+	}
+}
+
+func Test_FoldingRange_MarshalJSON(t *testing.T) {
+	ranges := []protocol.FoldingRange{
+		{StartLine: 2, EndLine: 10, Kind: protocol.FoldingRangeKindRegion, CollapsedText: "@section('content')"},
+		{StartLine: 0, StartCharacter: uint32Ptr(2), EndLine: 1, EndCharacter: uint32Ptr(4), Kind: protocol.FoldingRangeKindComment},
+	}
+
+	data, err := json.Marshal(ranges)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	expected := `[{"startLine":2,"endLine":10,"kind":"region","collapsedText":"@section('content')"},{"startLine":0,"startCharacter":2,"endLine":1,"endCharacter":4,"kind":"comment"}]`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
+
+func Test_FoldingRange_ClientKinds(t *testing.T) {
+	support := newClientSupport(t, `{"textDocument":{"foldingRange":{"foldingRangeKind":{"valueSet":["comment","imports","region"]}}}}`)
+
+	kinds := support.Capabilities().TextDocument.FoldingRange.FoldingRangeKind.ValueSet
+	if len(kinds) != 3 || kinds[2] != protocol.FoldingRangeKindRegion {
+		t.Fatalf("unexpected folding range kinds: %v", kinds)
+	}
+}
+
+func Test_FoldingRange_FilterDefaults(t *testing.T) {
+	ranges := []protocol.FoldingRange{
+		{StartLine: 5, EndLine: 3},
+		{StartLine: 4, StartCharacter: uint32Ptr(10), EndLine: 4, EndCharacter: uint32Ptr(20), CollapsedText: "..."},
+	}
+
+	filtered := protocol.FilterFoldingRanges(newClientSupport(t, `{}`), ranges)
+	if len(filtered) != 1 {
+		t.Fatalf("expected inverted range to be dropped, got %+v", filtered)
+	}
+	if filtered[0].StartCharacter == nil || filtered[0].CollapsedText != "" {
+		t.Fatalf("expected characters kept and collapsed text removed, got %+v", filtered[0])
+	}
+	if ranges[1].CollapsedText != "..." {
+		t.Fatalf("expected input to be left untouched")
+	}
+}
+
+func Test_FoldingRange_FilterLineFoldingOnly(t *testing.T) {
+	support := newClientSupport(t, `{"textDocument":{"foldingRange":{"lineFoldingOnly":true,"foldingRange":{"collapsedText":true}}}}`)
+	ranges := []protocol.FoldingRange{
+		{StartLine: 4, StartCharacter: uint32Ptr(10), EndLine: 4, EndCharacter: uint32Ptr(20)},
+		{StartLine: 2, StartCharacter: uint32Ptr(0), EndLine: 10, EndCharacter: uint32Ptr(4), CollapsedText: "@section('content')"},
+	}
+
+	filtered := protocol.FilterFoldingRanges(support, ranges)
+	if len(filtered) != 1 {
+		t.Fatalf("expected single line range to be dropped, got %+v", filtered)
+	}
+	if filtered[0].StartCharacter != nil || filtered[0].EndCharacter != nil {
+		t.Fatalf("expected character offsets to be removed, got %+v", filtered[0])
+	}
+	if filtered[0].CollapsedText != "@section('content')" {
+		t.Fatalf("expected collapsed text to be kept, got %q", filtered[0].CollapsedText)
+	}
+}
+
+func Test_FoldingRange_FilterRangeLimit(t *testing.T) {
+	support := newClientSupport(t, `{"textDocument":{"foldingRange":{"rangeLimit":2}}}`)
+	ranges := []protocol.FoldingRange{
+		{StartLine: 12, EndLine: 18},
+		{StartLine: 20, EndLine: 30},
+		{StartLine: 2, EndLine: 10},
+	}
+
+	filtered := protocol.FilterFoldingRanges(support, ranges)
+	if len(filtered) != 2 || filtered[0].StartLine != 12 || filtered[1].StartLine != 2 {
+		t.Fatalf("expected the two first starting ranges in input order, got %+v", filtered)
+	}
+}
+
+func Test_FoldingRange_Refresh(t *testing.T) {
+	router := protocol.NewRouter()
+	called := false
+	router.HandleFoldingRangeRefresh(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	result, err := router.Handle(context.Background(), newRequest(1, protocol.MethodWorkspaceFoldingRangeRefresh, ""))
+	if err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+	if !called || string(result.(json.RawMessage)) != "null" {
+		t.Fatalf("expected refresh handler to be called with null result, got %v", result)
+	}
+
+	if !newClientSupport(t, `{"workspace":{"foldingRange":{"refreshSupport":true}}}`).FoldingRangeRefresh() {
+		t.Fatalf("expected folding range refresh support")
+	}
+	if newClientSupport(t, `{}`).FoldingRangeRefresh() {
+		t.Fatalf("expected no folding range refresh support by default")
+	}
+}
@@ -0,0 +1,271 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// MethodTextDocumentFormatting method name of "textDocument/formatting".
+	MethodTextDocumentFormatting = "textDocument/formatting"
+
+	// MethodTextDocumentRangeFormatting method name of "textDocument/rangeFormatting".
+	MethodTextDocumentRangeFormatting = "textDocument/rangeFormatting"
+
+	// MethodTextDocumentRangesFormatting method name of "textDocument/rangesFormatting".
+	//
+	// @since 3.18.0
+	MethodTextDocumentRangesFormatting = "textDocument/rangesFormatting"
+
+	// MethodTextDocumentOnTypeFormatting method name of "textDocument/onTypeFormatting".
+	MethodTextDocumentOnTypeFormatting = "textDocument/onTypeFormatting"
+)
+
+// FormattingOptions - Value-object describing what options formatting should use.
+//
+// Properties other than the predefined ones are kept in Extra. Their values
+// are a bool, an int32 or a string.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#formattingOptions
+type FormattingOptions struct {
+	// Size of a tab in spaces.
+	TabSize uint32 `json:"tabSize"`
+
+	// Prefer spaces over tabs.
+	InsertSpaces bool `json:"insertSpaces"`
+
+	// Trim trailing whitespace on a line.
+	//
+	// @since 3.15.0
+	TrimTrailingWhitespace bool `json:"trimTrailingWhitespace,omitempty"`
+
+	// Insert a newline character at the end of the file if one does not exist.
+	//
+	// @since 3.15.0
+	InsertFinalNewline bool `json:"insertFinalNewline,omitempty"`
+
+	// Trim all newlines after the final newline at the end of the file.
+	//
+	// @since 3.15.0
+	TrimFinalNewlines bool `json:"trimFinalNewlines,omitempty"`
+
+	// Extra holds further properties to consider.
+	Extra map[string]any `json:"-"`
+}
+
+// formattingOptionKeys are the predefined FormattingOptions properties.
+var formattingOptionKeys = map[string]bool{
+	"tabSize":                true,
+	"insertSpaces":           true,
+	"trimTrailingWhitespace": true,
+	"insertFinalNewline":     true,
+	"trimFinalNewlines":      true,
+}
+
+func (o FormattingOptions) MarshalJSON() ([]byte, error) {
+	type formattingOptions FormattingOptions
+	data, err := json.Marshal(formattingOptions(o))
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Extra) == 0 {
+		return data, nil
+	}
+
+	fields := map[string]any{}
+	for key, value := range o.Extra {
+		if formattingOptionKeys[key] {
+			continue
+		}
+		value, err := formattingOptionValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid formatting option %q: %w", key, err)
+		}
+		fields[key] = value
+	}
+
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(data, &known); err != nil {
+		return nil, err
+	}
+	for key, value := range known {
+		fields[key] = value
+	}
+	return json.Marshal(fields)
+}
+
+func (o *FormattingOptions) UnmarshalJSON(data []byte) error {
+	type formattingOptions FormattingOptions
+	var known formattingOptions
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	*o = FormattingOptions(known)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for key, raw := range fields {
+		if formattingOptionKeys[key] {
+			continue
+		}
+
+		value, err := unmarshalFormattingOptionValue(raw)
+		if err != nil {
+			return fmt.Errorf("invalid formatting option %q: %w", key, err)
+		}
+		if o.Extra == nil {
+			o.Extra = make(map[string]any)
+		}
+		o.Extra[key] = value
+	}
+	return nil
+}
+
+// formattingOptionValue checks that value is a `boolean | integer | string`,
+// returning integers as int32. Whole float64 values, as produced by decoding
+// JSON into an interface, are accepted as integers.
+func formattingOptionValue(value any) (any, error) {
+	var i int64
+	switch v := value.(type) {
+	case bool, string:
+		return v, nil
+	case int:
+		i = int64(v)
+	case int8:
+		i = int64(v)
+	case int16:
+		i = int64(v)
+	case int32:
+		return v, nil
+	case int64:
+		i = v
+	case uint8:
+		i = int64(v)
+	case uint16:
+		i = int64(v)
+	case uint32:
+		i = int64(v)
+	case uint:
+		if uint64(v) > math.MaxInt32 {
+			return nil, fmt.Errorf("%d is out of the integer range", v)
+		}
+		i = int64(v)
+	case uint64:
+		if v > math.MaxInt32 {
+			return nil, fmt.Errorf("%d is out of the integer range", v)
+		}
+		i = int64(v)
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+		return int32(v), nil
+	default:
+		return nil, fmt.Errorf("%T is not a bool, integer or string", value)
+	}
+
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return nil, fmt.Errorf("%d is out of the integer range", i)
+	}
+	return int32(i), nil
+}
+
+// unmarshalFormattingOptionValue decodes a `boolean | integer | string` value.
+func unmarshalFormattingOptionValue(data []byte) (any, error) {
+	// null would decode into any of the types below without an error.
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil, errors.New("null is not a bool, integer or string")
+	}
+
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		return b, nil
+	}
+
+	var i int32
+	if err := json.Unmarshal(data, &i); err == nil {
+		return i, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("not a bool, integer or string: %s", data)
+}
+
+// DocumentFormattingParams defines the parameters for a textDocument/formatting request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentFormattingParams
+type DocumentFormattingParams struct {
+	WorkDoneProgressParams
+
+	// The document to format.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// The format options.
+	Options FormattingOptions `json:"options"`
+}
+
+// DocumentRangeFormattingParams defines the parameters for a textDocument/rangeFormatting request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentRangeFormattingParams
+type DocumentRangeFormattingParams struct {
+	WorkDoneProgressParams
+
+	// The document to format.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// The range to format.
+	Range Range `json:"range"`
+
+	// The format options.
+	Options FormattingOptions `json:"options"`
+}
+
+// DocumentRangesFormattingParams defines the parameters for a textDocument/rangesFormatting request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#documentRangesFormattingParams
+//
+// @since 3.18.0
+type DocumentRangesFormattingParams struct {
+	WorkDoneProgressParams
+
+	// The document to format.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// The ranges to format.
+	Ranges []Range `json:"ranges"`
+
+	// The format options.
+	Options FormattingOptions `json:"options"`
+}
+
+// DocumentOnTypeFormattingParams defines the parameters for a textDocument/onTypeFormatting request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentOnTypeFormattingParams
+type DocumentOnTypeFormattingParams struct {
+	// The document to format.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// The position around which the on type formatting should happen.
+	// This is not necessarily the exact position where the character denoted
+	// by the property `ch` got typed.
+	Position Position `json:"position"`
+
+	// The character that has been typed that triggered the formatting
+	// on type request. That is not necessarily the last character that
+	// got inserted into the document since the client could auto insert
+	// characters as well (e.g. like automatic brace completion).
+	Ch string `json:"ch"`
+
+	// The formatting options.
+	Options FormattingOptions `json:"options"`
+}
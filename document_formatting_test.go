@@ -0,0 +1,164 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentFormatting_OptionsPreserveExtraProperties(t *testing.T) {
+	data := []byte(`{
+		"tabSize": 4,
+		"insertSpaces": true,
+		"trimTrailingWhitespace": true,
+		"blade.wrapAttributes": "auto",
+		"blade.indentInnerHtml": false,
+		"blade.wrapLineLength": 120
+	}`)
+
+	var options protocol.FormattingOptions
+	if err := json.Unmarshal(data, &options); err != nil {
+		t.Fatalf("unmarshal FormattingOptions failed: %v", err)
+	}
+
+	if options.TabSize != 4 || !options.InsertSpaces || !options.TrimTrailingWhitespace {
+		t.Fatalf("unexpected predefined options: %+v", options)
+	}
+	if len(options.Extra) != 3 {
+		t.Fatalf("expected 3 extra options, got %+v", options.Extra)
+	}
+	if options.Extra["blade.wrapAttributes"] != "auto" {
+		t.Fatalf("expected string option, got %#v", options.Extra["blade.wrapAttributes"])
+	}
+	if options.Extra["blade.indentInnerHtml"] != false {
+		t.Fatalf("expected bool option, got %#v", options.Extra["blade.indentInnerHtml"])
+	}
+	if options.Extra["blade.wrapLineLength"] != int32(120) {
+		t.Fatalf("expected integer option, got %#v", options.Extra["blade.wrapLineLength"])
+	}
+
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("marshal FormattingOptions failed: %v", err)
+	}
+
+	expected := `{"blade.indentInnerHtml":false,"blade.wrapAttributes":"auto","blade.wrapLineLength":120,"insertSpaces":true,"tabSize":4,"trimTrailingWhitespace":true}`
+	if string(encoded) != expected {
+		t.Fatalf("expected %s, got %s", expected, encoded)
+	}
+}
+
+func Test_DocumentFormatting_OptionsWithoutExtra(t *testing.T) {
+	encoded, err := json.Marshal(protocol.FormattingOptions{TabSize: 2})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(encoded) != `{"tabSize":2,"insertSpaces":false}` {
+		t.Fatalf("unexpected payload: %s", encoded)
+	}
+
+	var options protocol.FormattingOptions
+	if err := json.Unmarshal(encoded, &options); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if options.Extra != nil {
+		t.Fatalf("expected no extra options, got %+v", options.Extra)
+	}
+}
+
+func Test_DocumentFormatting_OptionsRejectInvalidExtra(t *testing.T) {
+	var options protocol.FormattingOptions
+	if err := json.Unmarshal([]byte(`{"tabSize":4,"insertSpaces":true,"nested":{"a":1}}`), &options); err == nil {
+		t.Fatalf("expected error for object option value")
+	}
+	if err := json.Unmarshal([]byte(`{"tabSize":4,"insertSpaces":true,"ratio":1.5}`), &options); err == nil {
+		t.Fatalf("expected error for non-integer number option value")
+	}
+
+	if err := json.Unmarshal([]byte(`{"tabSize":4,"insertSpaces":true,"blade.indentInnerHtml":null}`), &options); err == nil {
+		t.Fatalf("expected error for null option value")
+	}
+
+	for _, value := range []any{1.5, int64(math.MaxInt32) + 1, math.MinInt32 - 1, uint64(math.MaxUint64), nil} {
+		options = protocol.FormattingOptions{Extra: map[string]any{"value": value}}
+		if _, err := json.Marshal(options); err == nil {
+			t.Fatalf("expected error marshalling option value %#v", value)
+		}
+	}
+}
+
+func Test_DocumentFormatting_OptionsMarshalIntegers(t *testing.T) {
+	// Whole float64 values are what decoding JSON into an interface yields.
+	options := protocol.FormattingOptions{TabSize: 2, Extra: map[string]any{
+		"a": float64(120),
+		"b": int64(-3),
+		"c": uint(7),
+	}}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	expected := `{"a":120,"b":-3,"c":7,"insertSpaces":false,"tabSize":2}`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
+
+func Test_DocumentFormatting_ParamsUnmarshalValidJSON(t *testing.T) {
+	options := `"options":{"tabSize":4,"insertSpaces":true}`
+
+	var format protocol.DocumentFormattingParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///a.blade.php"},`+options+`}`), &format); err != nil {
+		t.Fatalf("unmarshal DocumentFormattingParams failed: %v", err)
+	}
+	if format.TextDocument.URI != "file:///a.blade.php" || format.Options.TabSize != 4 {
+		t.Fatalf("unexpected DocumentFormattingParams: %+v", format)
+	}
+
+	var rangeFormat protocol.DocumentRangeFormattingParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///a.blade.php"},"range":{"start":{"line":1,"character":0},"end":{"line":3,"character":0}},`+options+`}`), &rangeFormat); err != nil {
+		t.Fatalf("unmarshal DocumentRangeFormattingParams failed: %v", err)
+	}
+	if rangeFormat.Range.End.Line != 3 {
+		t.Fatalf("unexpected DocumentRangeFormattingParams: %+v", rangeFormat)
+	}
+
+	var rangesFormat protocol.DocumentRangesFormattingParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///a.blade.php"},"ranges":[{"start":{"line":1,"character":0},"end":{"line":2,"character":0}},{"start":{"line":5,"character":0},"end":{"line":6,"character":0}}],`+options+`}`), &rangesFormat); err != nil {
+		t.Fatalf("unmarshal DocumentRangesFormattingParams failed: %v", err)
+	}
+	if len(rangesFormat.Ranges) != 2 || rangesFormat.Ranges[1].Start.Line != 5 {
+		t.Fatalf("unexpected DocumentRangesFormattingParams: %+v", rangesFormat)
+	}
+
+	var onType protocol.DocumentOnTypeFormattingParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///a.php"},"position":{"line":2,"character":9},"ch":";",`+options+`}`), &onType); err != nil {
+		t.Fatalf("unmarshal DocumentOnTypeFormattingParams failed: %v", err)
+	}
+	if onType.Ch != ";" || onType.Position.Character != 9 {
+		t.Fatalf("unexpected DocumentOnTypeFormattingParams: %+v", onType)
+	}
+}
+
+func Test_DocumentFormatting_RangesSupport(t *testing.T) {
+	support := newClientSupport(t, `{"textDocument":{"rangeFormatting":{"dynamicRegistration":true,"rangesSupport":true}}}`)
+	if !support.RangesFormatting() {
+		t.Fatalf("expected rangesSupport=true")
+	}
+	if !support.DynamicRegistration(protocol.MethodTextDocumentRangesFormatting) {
+		t.Fatalf("expected dynamic registration for rangesFormatting")
+	}
+
+	var caps protocol.ServerCapabilities
+	if err := json.Unmarshal([]byte(`{"documentRangeFormattingProvider":{"rangesSupport":true}}`), &caps); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	provider := caps.DocumentRangeFormattingProvider
+	if provider == nil || provider.Options == nil || !provider.Options.RangesSupport {
+		t.Fatalf("expected documentRangeFormattingProvider.rangesSupport=true, got %+v", provider)
+	}
+}
@@ -0,0 +1,44 @@
+package protocol
+
+const (
+	// MethodTextDocumentDocumentHighlight method name of "textDocument/documentHighlight".
+	MethodTextDocumentDocumentHighlight = "textDocument/documentHighlight"
+)
+
+// DocumentHighlightParams defines the parameters for a textDocument/documentHighlight request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentHighlightParams
+type DocumentHighlightParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocumentPositionParams
+}
+
+// DocumentHighlight - A document highlight is a range inside a text document
+// which deserves special attention. Usually a document highlight is visualized
+// by changing the background color of its range.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentHighlight
+type DocumentHighlight struct {
+	// The range this highlight applies to.
+	Range Range `json:"range"`
+
+	// The highlight kind, default is DocumentHighlightKindText.
+	Kind DocumentHighlightKind `json:"kind,omitempty"`
+}
+
+// DocumentHighlightKind - A document highlight kind.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentHighlightKind
+type DocumentHighlightKind int
+
+const (
+	// A textual occurrence.
+	DocumentHighlightKindText DocumentHighlightKind = 1
+
+	// Read-access of a symbol, like reading a variable.
+	DocumentHighlightKindRead DocumentHighlightKind = 2
+
+	// Write-access of a symbol, like writing to a variable.
+	DocumentHighlightKindWrite DocumentHighlightKind = 3
+)
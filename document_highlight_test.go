@@ -0,0 +1,48 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentHighlight_UnmarshalValidJSON(t *testing.T) {
+	var params protocol.DocumentHighlightParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///a.php"},"position":{"line":2,"character":3}}`), &params); err != nil {
+		t.Fatalf("unmarshal DocumentHighlightParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///a.php" || params.Position.Character != 3 {
+		t.Fatalf("unexpected DocumentHighlightParams: %+v", params)
+	}
+
+	var highlights []protocol.DocumentHighlight
+	data := []byte(`[
+		{"range": {"start": {"line": 1, "character": 0}, "end": {"line": 1, "character": 4}}, "kind": 3},
+		{"range": {"start": {"line": 5, "character": 2}, "end": {"line": 5, "character": 6}}}
+	]`)
+	if err := json.Unmarshal(data, &highlights); err != nil {
+		t.Fatalf("unmarshal []DocumentHighlight failed: %v", err)
+	}
+
+	if len(highlights) != 2 {
+		t.Fatalf("expected 2 highlights, got %d", len(highlights))
+	}
+	if highlights[0].Kind != protocol.DocumentHighlightKindWrite {
+		t.Fatalf("expected write highlight, got %d", highlights[0].Kind)
+	}
+	if highlights[1].Kind != 0 {
+		t.Fatalf("expected omitted kind, got %d", highlights[1].Kind)
+	}
+}
+
+func Test_DocumentHighlight_MarshalOmitsKind(t *testing.T) {
+	data, err := json.Marshal(protocol.DocumentHighlight{})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	if string(data) != `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}` {
+		t.Fatalf("unexpected payload: %s", data)
+	}
+}
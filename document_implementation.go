@@ -0,0 +1,22 @@
+package protocol
+
+const (
+	// MethodTextDocumentImplementation method name of "textDocument/implementation".
+	MethodTextDocumentImplementation = "textDocument/implementation"
+)
+
+// ImplementationParams defines the parameters for a textDocument/implementation request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#implementationParams
+//
+// @since 3.6.0
+type ImplementationParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocumentPositionParams
+}
+
+// ImplementationResponse represents the result of a textDocument/implementation request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_implementation
+type ImplementationResponse = LocationResult
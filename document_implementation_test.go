@@ -0,0 +1,55 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentImplementation_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.ImplementationParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///app/Contracts/Cache.php"},"position":{"line":7,"character":10},"workDoneToken":1}`), &params); err != nil {
+		t.Fatalf("unmarshal ImplementationParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///app/Contracts/Cache.php" || params.Position.Line != 7 {
+		t.Fatalf("unexpected ImplementationParams: %+v", params)
+	}
+	if params.WorkDoneToken == nil {
+		t.Fatalf("expected workDoneToken to be set")
+	}
+}
+
+func Test_DocumentImplementation_ResponseUnmarshal(t *testing.T) {
+	var single protocol.ImplementationResponse
+	if err := json.Unmarshal([]byte(`{"uri":"file:///a.php","range":{"start":{"line":1,"character":0},"end":{"line":1,"character":1}}}`), &single); err != nil {
+		t.Fatalf("unmarshal single location failed: %v", err)
+	}
+	if single.Location == nil || single.Location.URI != "file:///a.php" {
+		t.Fatalf("expected single location, got %+v", single)
+	}
+
+	var list protocol.ImplementationResponse
+	if err := json.Unmarshal([]byte(`[{"uri":"file:///a.php","range":{"start":{"line":1,"character":0},"end":{"line":1,"character":1}}},{"uri":"file:///b.php","range":{"start":{"line":2,"character":0},"end":{"line":2,"character":1}}}]`), &list); err != nil {
+		t.Fatalf("unmarshal location list failed: %v", err)
+	}
+	if len(list.LocationList) != 2 || list.LocationList[1].URI != "file:///b.php" {
+		t.Fatalf("expected location list, got %+v", list)
+	}
+
+	var null protocol.ImplementationResponse
+	if err := json.Unmarshal([]byte(`null`), &null); err != nil {
+		t.Fatalf("unmarshal null failed: %v", err)
+	}
+	if !null.Null {
+		t.Fatalf("expected null response")
+	}
+
+	data, err := json.Marshal(null)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != "null" {
+		t.Fatalf("expected null payload, got %s", data)
+	}
+}
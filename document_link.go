@@ -0,0 +1,66 @@
+package protocol
+
+import "context"
+
+const (
+	// MethodTextDocumentDocumentLink method name of "textDocument/documentLink".
+	MethodTextDocumentDocumentLink = "textDocument/documentLink"
+
+	// MethodDocumentLinkResolve method name of "documentLink/resolve".
+	MethodDocumentLinkResolve = "documentLink/resolve"
+)
+
+// DocumentLinkParams defines the parameters for a textDocument/documentLink request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentLinkParams
+type DocumentLinkParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// The document to provide document links for.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// DocumentLink - A document link is a range in a text document that links to
+// an internal or external resource, like another text document or a web site.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentLink
+type DocumentLink struct {
+	// The range this link applies to.
+	Range Range `json:"range"`
+
+	// The uri this link points to. If missing a resolve request is sent later.
+	Target DocumentURI `json:"target,omitempty"`
+
+	// The tooltip text when you hover over this link.
+	//
+	// If a tooltip is provided, is will be displayed in a string that includes
+	// instructions on how to trigger the link, such as `{0} (ctrl + click)`.
+	// The specific instructions vary depending on OS, user settings, and
+	// localization.
+	//
+	// @since 3.15.0
+	Tooltip string `json:"tooltip,omitempty"`
+
+	// A data entry field that is preserved on a document link between a
+	// DocumentLinkRequest and a DocumentLinkResolveRequest.
+	Data LSPAny `json:"data,omitempty"`
+}
+
+// DocumentLinkResolver returns a `documentLink/resolve` handler that decodes
+// the data of an unresolved document link into D and lets fn resolve it.
+//
+// Links that already carry a target are returned unchanged.
+func DocumentLinkResolver[D any](fn func(ctx context.Context, link DocumentLink, data D) (DocumentLink, error)) func(ctx context.Context, link DocumentLink) (DocumentLink, error) {
+	return func(ctx context.Context, link DocumentLink) (DocumentLink, error) {
+		if link.Target != "" {
+			return link, nil
+		}
+
+		data, err := decodeResolveData[D](link.Data)
+		if err != nil {
+			return link, NewResponseError(RPCInvalidParams, "invalid document link data: %s", err.Error())
+		}
+		return fn(ctx, link, data)
+	}
+}
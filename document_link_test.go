@@ -0,0 +1,92 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+type viewLinkData struct {
+	View string `json:"view"`
+}
+
+func Test_DocumentLink_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.DocumentLinkParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///app/Http/Controllers/UserController.php"},"partialResultToken":1}`), &params); err != nil {
+		t.Fatalf("unmarshal DocumentLinkParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///app/Http/Controllers/UserController.php" || params.PartialResultToken == nil {
+		t.Fatalf("unexpected DocumentLinkParams: %+v", params)
+	}
+}
+
+func Test_DocumentLink_MarshalJSON(t *testing.T) {
+	link := protocol.DocumentLink{
+		Range:   protocol.Range{Start: protocol.Position{Line: 14, Character: 21}, End: protocol.Position{Line: 14, Character: 34}},
+		Target:  "file:///resources/views/users/index.blade.php",
+		Tooltip: "Open view users.index",
+	}
+
+	data, err := json.Marshal(link)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	expected := `{"range":{"start":{"line":14,"character":21},"end":{"line":14,"character":34}},"target":"file:///resources/views/users/index.blade.php","tooltip":"Open view users.index"}`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
+
+func Test_DocumentLink_ResolverRoundTripsData(t *testing.T) {
+	router := protocol.NewRouter()
+	router.HandleDocumentLinkResolve(protocol.DocumentLinkResolver(func(ctx context.Context, link protocol.DocumentLink, data viewLinkData) (protocol.DocumentLink, error) {
+		if data.View != "users.index" {
+			t.Fatalf("unexpected link data: %+v", data)
+		}
+		link.Target = "file:///resources/views/users/index.blade.php"
+		return link, nil
+	}))
+
+	result, err := router.Handle(context.Background(), newRequest(1, protocol.MethodDocumentLinkResolve,
+		`{"range":{"start":{"line":14,"character":21},"end":{"line":14,"character":34}},"data":{"view":"users.index"}}`))
+	if err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+
+	expected := `{"range":{"start":{"line":14,"character":21},"end":{"line":14,"character":34}},"target":"file:///resources/views/users/index.blade.php","data":{"view":"users.index"}}`
+	if raw := result.(json.RawMessage); string(raw) != expected {
+		t.Fatalf("expected %s, got %s", expected, raw)
+	}
+}
+
+func Test_DocumentLink_ResolverKeepsResolvedLink(t *testing.T) {
+	resolve := protocol.DocumentLinkResolver(func(ctx context.Context, link protocol.DocumentLink, data viewLinkData) (protocol.DocumentLink, error) {
+		t.Fatalf("resolver called for resolved link")
+		return link, nil
+	})
+
+	link := protocol.DocumentLink{Target: "file:///routes/web.php"}
+	resolved, err := resolve(context.Background(), link)
+	if err != nil || resolved.Target != link.Target {
+		t.Fatalf("expected link to be kept, got %+v, %v", resolved, err)
+	}
+
+	resolve = protocol.DocumentLinkResolver(func(ctx context.Context, link protocol.DocumentLink, data viewLinkData) (protocol.DocumentLink, error) {
+		t.Fatalf("resolver called with invalid data")
+		return link, nil
+	})
+	_, err = resolve(context.Background(), protocol.DocumentLink{Data: []any{"users.index"}})
+	expectErrorCode(t, err, protocol.RPCInvalidParams)
+}
+
+func Test_DocumentLink_TooltipSupport(t *testing.T) {
+	if !newClientSupport(t, `{"textDocument":{"documentLink":{"tooltipSupport":true}}}`).DocumentLinkTooltip() {
+		t.Fatalf("expected tooltip support")
+	}
+	if newClientSupport(t, `{}`).DocumentLinkTooltip() {
+		t.Fatalf("expected no tooltip support by default")
+	}
+}
@@ -0,0 +1,21 @@
+package protocol
+
+const (
+	MethodTextDocumentPublishDiagnostics = "textDocument/publishDiagnostics"
+)
+
+// PublishDiagnosticsParams - The parameters of a publish diagnostics notification.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#publishDiagnosticsParams
+type PublishDiagnosticsParams struct {
+	// The URI for which diagnostic information is reported.
+	URI DocumentURI `json:"uri"`
+
+	// Optional the version number of the document the diagnostics are published for.
+	//
+	// @since 3.15.0
+	Version *int `json:"version,omitempty"`
+
+	// An array of diagnostic information items.
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
@@ -0,0 +1,54 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_PublishDiagnostics_UnmarshalValidJSON(t *testing.T) {
+	data := []byte(`{
+		"uri": "file:///app/Models/User.php",
+		"version": 3,
+		"diagnostics": [
+			{
+				"range": {"start": {"line": 1, "character": 0}, "end": {"line": 1, "character": 5}},
+				"severity": 1,
+				"source": "phpstan",
+				"message": "Undefined variable"
+			}
+		]
+	}`)
+
+	var params protocol.PublishDiagnosticsParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if params.URI != "file:///app/Models/User.php" {
+		t.Fatalf("unexpected uri: %q", params.URI)
+	}
+	if params.Version == nil || *params.Version != 3 {
+		t.Fatalf("expected version 3, got %v", params.Version)
+	}
+	if len(params.Diagnostics) != 1 || params.Diagnostics[0].Source != "phpstan" {
+		t.Fatalf("unexpected diagnostics: %+v", params.Diagnostics)
+	}
+}
+
+func Test_PublishDiagnostics_MarshalEmptyDiagnostics(t *testing.T) {
+	params := protocol.PublishDiagnosticsParams{
+		URI:         "file:///a.php",
+		Diagnostics: []protocol.Diagnostic{},
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	if string(data) != `{"uri":"file:///a.php","diagnostics":[]}` {
+		t.Fatalf("unexpected payload: %s", data)
+	}
+}
@@ -0,0 +1,25 @@
+package protocol
+
+const (
+	// MethodTextDocumentReferences method name of "textDocument/references".
+	MethodTextDocumentReferences = "textDocument/references"
+)
+
+// ReferenceParams defines the parameters for a textDocument/references request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#referenceParams
+type ReferenceParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocumentPositionParams
+
+	Context ReferenceContext `json:"context"`
+}
+
+// ReferenceContext - Additional information passed with a references request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#referenceContext
+type ReferenceContext struct {
+	// Include the declaration of the current symbol.
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
@@ -0,0 +1,55 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentReferences_ParamsUnmarshalValidJSON(t *testing.T) {
+	data := []byte(`{
+		"textDocument": {"uri": "file:///routes/web.php"},
+		"position": {"line": 4, "character": 12},
+		"context": {"includeDeclaration": true},
+		"partialResultToken": "refs"
+	}`)
+
+	var params protocol.ReferenceParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatalf("unmarshal ReferenceParams failed: %v", err)
+	}
+
+	if params.TextDocument.URI != "file:///routes/web.php" || params.Position.Line != 4 {
+		t.Fatalf("unexpected ReferenceParams: %+v", params)
+	}
+	if !params.Context.IncludeDeclaration {
+		t.Fatalf("expected context.includeDeclaration=true")
+	}
+	if params.PartialResultToken == nil || params.PartialResultToken.String() != "refs" {
+		t.Fatalf("expected partialResultToken=refs, got %v", params.PartialResultToken)
+	}
+}
+
+func Test_DocumentReferences_ParamsMarshalContext(t *testing.T) {
+	params := protocol.ReferenceParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: "file:///a.php"},
+		},
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	ctx, ok := payload["context"].(map[string]any)
+	if !ok || ctx["includeDeclaration"] != false {
+		t.Fatalf("expected context.includeDeclaration to always be sent: %s", data)
+	}
+}
@@ -0,0 +1,123 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// MethodTextDocumentRename method name of "textDocument/rename".
+	MethodTextDocumentRename = "textDocument/rename"
+
+	// MethodTextDocumentPrepareRename method name of "textDocument/prepareRename".
+	MethodTextDocumentPrepareRename = "textDocument/prepareRename"
+)
+
+// RenameParams defines the parameters for a textDocument/rename request.
+//
+// The result of the request is a WorkspaceEdit or null.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#renameParams
+type RenameParams struct {
+	WorkDoneProgressParams
+	TextDocumentPositionParams
+
+	// The new name of the symbol. If the given name is not valid the
+	// request must return a ResponseError with an appropriate message set.
+	NewName string `json:"newName"`
+}
+
+// PrepareRenameParams defines the parameters for a textDocument/prepareRename request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#prepareRenameParams
+//
+// @since 3.12.0
+type PrepareRenameParams struct {
+	WorkDoneProgressParams
+	TextDocumentPositionParams
+}
+
+// PrepareRenamePlaceholder - The range of the string to rename together with
+// a placeholder text of the string content to be renamed.
+type PrepareRenamePlaceholder struct {
+	Range       Range  `json:"range"`
+	Placeholder string `json:"placeholder"`
+}
+
+// PrepareRenameDefaultBehavior - Tells the client to use its default behavior
+// to compute the rename range.
+type PrepareRenameDefaultBehavior struct {
+	DefaultBehavior bool `json:"defaultBehavior"`
+}
+
+// PrepareRenameResult represents the result of a textDocument/prepareRename request.
+//
+// It can be a Range, a range with a placeholder, a default behavior flag or null.
+// Null signals that a rename at the given position is not valid.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_prepareRename
+type PrepareRenameResult struct {
+	Range           *Range
+	Placeholder     *PrepareRenamePlaceholder
+	DefaultBehavior *PrepareRenameDefaultBehavior
+	Null            bool
+}
+
+func (r PrepareRenameResult) MarshalJSON() ([]byte, error) {
+	if r.Range != nil {
+		return json.Marshal(r.Range)
+	}
+	if r.Placeholder != nil {
+		return json.Marshal(r.Placeholder)
+	}
+	if r.DefaultBehavior != nil {
+		return json.Marshal(r.DefaultBehavior)
+	}
+	return []byte("null"), nil
+}
+
+func (r *PrepareRenameResult) UnmarshalJSON(data []byte) error {
+	// Make sure object is reset.
+	*r = PrepareRenameResult{}
+
+	if string(data) == "null" {
+		r.Null = true
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return errors.New("invalid prepare rename result: not null or an object")
+	}
+
+	if _, ok := fields["defaultBehavior"]; ok {
+		var behavior PrepareRenameDefaultBehavior
+		if err := json.Unmarshal(data, &behavior); err != nil {
+			return err
+		}
+		r.DefaultBehavior = &behavior
+		return nil
+	}
+
+	if _, ok := fields["placeholder"]; ok {
+		var placeholder PrepareRenamePlaceholder
+		if err := json.Unmarshal(data, &placeholder); err != nil {
+			return err
+		}
+		r.Placeholder = &placeholder
+		return nil
+	}
+
+	_, hasStart := fields["start"]
+	_, hasEnd := fields["end"]
+	if hasStart && hasEnd {
+		var rng Range
+		if err := json.Unmarshal(data, &rng); err != nil {
+			return err
+		}
+		r.Range = &rng
+		return nil
+	}
+
+	return errors.New("invalid prepare rename result: not a Range, {range, placeholder} or {defaultBehavior}")
+}
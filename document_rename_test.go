@@ -0,0 +1,136 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentRename_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.RenameParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///resources/views/components/alert.blade.php"},"position":{"line":0,"character":5},"newName":"notice"}`), &params); err != nil {
+		t.Fatalf("unmarshal RenameParams failed: %v", err)
+	}
+	if params.NewName != "notice" || params.Position.Character != 5 {
+		t.Fatalf("unexpected RenameParams: %+v", params)
+	}
+
+	var prepare protocol.PrepareRenameParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///a.php"},"position":{"line":1,"character":2}}`), &prepare); err != nil {
+		t.Fatalf("unmarshal PrepareRenameParams failed: %v", err)
+	}
+	if prepare.TextDocument.URI != "file:///a.php" {
+		t.Fatalf("unexpected PrepareRenameParams: %+v", prepare)
+	}
+}
+
+func Test_DocumentRename_PrepareRenameResultRoundTrip(t *testing.T) {
+	rng := `{"start":{"line":1,"character":2},"end":{"line":1,"character":7}}`
+
+	tests := []struct {
+		name  string
+		json  string
+		check func(t *testing.T, r protocol.PrepareRenameResult)
+	}{
+		{
+			name: "null",
+			json: `null`,
+			check: func(t *testing.T, r protocol.PrepareRenameResult) {
+				if !r.Null {
+					t.Fatalf("expected null result, got %+v", r)
+				}
+			},
+		},
+		{
+			name: "range",
+			json: rng,
+			check: func(t *testing.T, r protocol.PrepareRenameResult) {
+				if r.Range == nil || r.Range.End.Character != 7 {
+					t.Fatalf("expected range, got %+v", r)
+				}
+			},
+		},
+		{
+			name: "placeholder",
+			json: `{"range":` + rng + `,"placeholder":"alert"}`,
+			check: func(t *testing.T, r protocol.PrepareRenameResult) {
+				if r.Placeholder == nil || r.Placeholder.Placeholder != "alert" || r.Placeholder.Range.Start.Character != 2 {
+					t.Fatalf("expected placeholder, got %+v", r)
+				}
+			},
+		},
+		{
+			name: "default behavior",
+			json: `{"defaultBehavior":true}`,
+			check: func(t *testing.T, r protocol.PrepareRenameResult) {
+				if r.DefaultBehavior == nil || !r.DefaultBehavior.DefaultBehavior {
+					t.Fatalf("expected default behavior, got %+v", r)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result protocol.PrepareRenameResult
+			if err := json.Unmarshal([]byte(tt.json), &result); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			tt.check(t, result)
+
+			data, err := json.Marshal(result)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			if string(data) != tt.json {
+				t.Fatalf("expected round trip %s, got %s", tt.json, data)
+			}
+		})
+	}
+}
+
+func Test_DocumentRename_PrepareRenameResultInvalid(t *testing.T) {
+	for _, input := range []string{`{"line":1}`, `[1,2]`, `true`} {
+		var result protocol.PrepareRenameResult
+		if err := json.Unmarshal([]byte(input), &result); err == nil {
+			t.Fatalf("expected error for %s", input)
+		}
+	}
+}
+
+func Test_DocumentRename_RouterReturnsWorkspaceEdit(t *testing.T) {
+	router := protocol.NewRouter()
+	router.HandleRename(func(ctx context.Context, params protocol.RenameParams) (*protocol.WorkspaceEdit, error) {
+		if params.NewName == "" {
+			return nil, nil
+		}
+		return &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+				"file:///a.php": {{NewText: params.NewName}},
+			},
+		}, nil
+	})
+
+	result, err := router.Handle(context.Background(), newRequest(1, protocol.MethodTextDocumentRename, `{"textDocument":{"uri":"file:///a.php"},"position":{"line":0,"character":0},"newName":"notice"}`))
+	if err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+
+	var edit protocol.WorkspaceEdit
+	if err := json.Unmarshal(result.(json.RawMessage), &edit); err != nil {
+		t.Fatalf("unmarshal WorkspaceEdit failed: %v", err)
+	}
+	if edits := edit.Changes["file:///a.php"]; len(edits) != 1 || edits[0].NewText != "notice" {
+		t.Fatalf("unexpected WorkspaceEdit: %+v", edit)
+	}
+
+	result, err = router.Handle(context.Background(), newRequest(2, protocol.MethodTextDocumentRename, `{"textDocument":{"uri":"file:///a.php"},"position":{"line":0,"character":0},"newName":""}`))
+	if err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	if string(result.(json.RawMessage)) != "null" {
+		t.Fatalf("expected null result, got %s", result)
+	}
+}
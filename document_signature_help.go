@@ -0,0 +1,171 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// MethodTextDocumentSignatureHelp method name of "textDocument/signatureHelp".
+	MethodTextDocumentSignatureHelp = "textDocument/signatureHelp"
+)
+
+// SignatureHelpParams defines the parameters for a textDocument/signatureHelp request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#signatureHelpParams
+type SignatureHelpParams struct {
+	WorkDoneProgressParams
+	TextDocumentPositionParams
+
+	// The signature help context. This is only available if the client
+	// specifies to send this using the client capability
+	// `textDocument.signatureHelp.contextSupport === true`
+	//
+	// @since 3.15.0
+	Context *SignatureHelpContext `json:"context,omitempty"`
+}
+
+// SignatureHelpTriggerKind - How a signature help was triggered.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#signatureHelpTriggerKind
+//
+// @since 3.15.0
+type SignatureHelpTriggerKind int
+
+const (
+	// Signature help was invoked manually by the user or by a command.
+	SignatureHelpTriggerKindInvoked SignatureHelpTriggerKind = 1
+
+	// Signature help was triggered by a trigger character.
+	SignatureHelpTriggerKindTriggerCharacter SignatureHelpTriggerKind = 2
+
+	// Signature help was triggered by the cursor moving or by the document
+	// content changing.
+	SignatureHelpTriggerKindContentChange SignatureHelpTriggerKind = 3
+)
+
+// SignatureHelpContext - Additional information about the context in which a
+// signature help request was triggered.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#signatureHelpContext
+//
+// @since 3.15.0
+type SignatureHelpContext struct {
+	// Action that caused signature help to be triggered.
+	TriggerKind SignatureHelpTriggerKind `json:"triggerKind"`
+
+	// Character that caused signature help to be triggered.
+	//
+	// This is undefined when triggerKind !==
+	// SignatureHelpTriggerKind.TriggerCharacter
+	TriggerCharacter string `json:"triggerCharacter,omitempty"`
+
+	// `true` if signature help was already showing when it was triggered.
+	//
+	// Retriggers occur when the signature help is already active and can be
+	// caused by actions such as typing a trigger character, a cursor move, or
+	// document content changes.
+	IsRetrigger bool `json:"isRetrigger"`
+
+	// The currently active `SignatureHelp`.
+	//
+	// The `activeSignatureHelp` has its `SignatureHelp.activeSignature` field
+	// updated based on the user navigating through available signatures.
+	ActiveSignatureHelp *SignatureHelp `json:"activeSignatureHelp,omitempty"`
+}
+
+// SignatureHelp - Signature help represents the signature of something
+// callable. There can be multiple signature but only one
+// active and only one active parameter.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#signatureHelp
+type SignatureHelp struct {
+	// One or more signatures. If no signatures are available the signature
+	// help request should return `null`.
+	Signatures []SignatureInformation `json:"signatures"`
+
+	// The active signature. If omitted or the value lies outside the
+	// range of `signatures` the value defaults to zero or is ignored if
+	// the `SignatureHelp` has no signatures.
+	ActiveSignature *uint32 `json:"activeSignature,omitempty"`
+
+	// The active parameter of the active signature. If omitted or the value
+	// lies outside the range of `signatures[activeSignature].parameters`
+	// defaults to 0 if the active signature has parameters. If
+	// the active signature has no parameters it is ignored.
+	ActiveParameter *uint32 `json:"activeParameter,omitempty"`
+}
+
+// SignatureInformation - Represents the signature of something callable. A
+// signature can have a label, like a function-name, a doc-comment, and
+// a set of parameters.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#signatureInformation
+type SignatureInformation struct {
+	// The label of this signature. Will be shown in the UI.
+	Label string `json:"label"`
+
+	// The human-readable doc-comment of this signature. Will be shown
+	// in the UI but can be omitted.
+	Documentation *StringOrMarkupContent `json:"documentation,omitempty"`
+
+	// The parameters of this signature.
+	Parameters []ParameterInformation `json:"parameters,omitempty"`
+
+	// The index of the active parameter.
+	//
+	// If provided, this is used in place of `SignatureHelp.activeParameter`.
+	//
+	// @since 3.16.0
+	ActiveParameter *uint32 `json:"activeParameter,omitempty"`
+}
+
+// ParameterInformation - Represents a parameter of a callable-signature. A
+// parameter can have a label and a doc-comment.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#parameterInformation
+type ParameterInformation struct {
+	// The label of this parameter information.
+	Label ParameterLabel `json:"label"`
+
+	// The human-readable doc-comment of this parameter. Will be shown
+	// in the UI but can be omitted.
+	Documentation *StringOrMarkupContent `json:"documentation,omitempty"`
+}
+
+// ParameterLabel is the `string | [uinteger, uinteger]` label of a parameter.
+//
+// Either a string, which must be a substring of its containing signature
+// label, or inclusive start and exclusive end offsets within the signature
+// label. Offsets are only valid if the client announced
+// `labelOffsetSupport`.
+type ParameterLabel struct {
+	String  string
+	Offsets *[2]uint32
+}
+
+func (l ParameterLabel) MarshalJSON() ([]byte, error) {
+	if l.Offsets != nil {
+		return json.Marshal(l.Offsets)
+	}
+	return json.Marshal(l.String)
+}
+
+func (l *ParameterLabel) UnmarshalJSON(data []byte) error {
+	*l = ParameterLabel{}
+
+	if err := json.Unmarshal(data, &l.String); err == nil {
+		return nil
+	}
+
+	var offsets [2]uint32
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err == nil && len(raw) == 2 {
+		if err := json.Unmarshal(data, &offsets); err == nil {
+			l.Offsets = &offsets
+			return nil
+		}
+	}
+
+	return errors.New("invalid parameter label: not a string or [uinteger, uinteger]")
+}
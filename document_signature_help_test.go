@@ -0,0 +1,119 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentSignatureHelp_ParamsUnmarshalValidJSON(t *testing.T) {
+	data := []byte(`{
+		"textDocument": {"uri": "file:///routes/web.php"},
+		"position": {"line": 3, "character": 14},
+		"context": {
+			"triggerKind": 2,
+			"triggerCharacter": ",",
+			"isRetrigger": true,
+			"activeSignatureHelp": {
+				"signatures": [{"label": "route(string $name)"}],
+				"activeSignature": 0
+			}
+		}
+	}`)
+
+	var params protocol.SignatureHelpParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatalf("unmarshal SignatureHelpParams failed: %v", err)
+	}
+
+	if params.Context == nil || params.Context.TriggerKind != protocol.SignatureHelpTriggerKindTriggerCharacter {
+		t.Fatalf("unexpected context: %+v", params.Context)
+	}
+	if params.Context.TriggerCharacter != "," || !params.Context.IsRetrigger {
+		t.Fatalf("unexpected context: %+v", params.Context)
+	}
+	active := params.Context.ActiveSignatureHelp
+	if active == nil || len(active.Signatures) != 1 || active.ActiveSignature == nil || *active.ActiveSignature != 0 {
+		t.Fatalf("unexpected activeSignatureHelp: %+v", active)
+	}
+}
+
+func Test_DocumentSignatureHelp_UnmarshalLabelsAndDocumentation(t *testing.T) {
+	data := []byte(`{
+		"signatures": [
+			{
+				"label": "route(string $name, array $parameters = [])",
+				"documentation": {"kind": "markdown", "value": "Generate the URL to a named route."},
+				"parameters": [
+					{"label": [6, 18], "documentation": "The route name."},
+					{"label": "array $parameters = []"}
+				],
+				"activeParameter": 1
+			}
+		],
+		"activeSignature": 0,
+		"activeParameter": 0
+	}`)
+
+	var help protocol.SignatureHelp
+	if err := json.Unmarshal(data, &help); err != nil {
+		t.Fatalf("unmarshal SignatureHelp failed: %v", err)
+	}
+
+	signature := help.Signatures[0]
+	if signature.Documentation == nil || signature.Documentation.MarkupContent == nil || signature.Documentation.MarkupContent.Kind != protocol.MarkupKindMarkdown {
+		t.Fatalf("expected markdown documentation, got %+v", signature.Documentation)
+	}
+	if signature.ActiveParameter == nil || *signature.ActiveParameter != 1 {
+		t.Fatalf("expected activeParameter=1, got %v", signature.ActiveParameter)
+	}
+
+	offsets := signature.Parameters[0]
+	if offsets.Label.Offsets == nil || offsets.Label.Offsets[0] != 6 || offsets.Label.Offsets[1] != 18 {
+		t.Fatalf("expected offset label, got %+v", offsets.Label)
+	}
+	if offsets.Documentation == nil || offsets.Documentation.String == nil || *offsets.Documentation.String != "The route name." {
+		t.Fatalf("expected string documentation, got %+v", offsets.Documentation)
+	}
+
+	str := signature.Parameters[1]
+	if str.Label.Offsets != nil || str.Label.String != "array $parameters = []" {
+		t.Fatalf("expected string label, got %+v", str.Label)
+	}
+	if str.Documentation != nil {
+		t.Fatalf("expected no documentation, got %+v", str.Documentation)
+	}
+}
+
+func Test_DocumentSignatureHelp_MarshalLabels(t *testing.T) {
+	documentation := "The view name."
+	help := protocol.SignatureHelp{
+		Signatures: []protocol.SignatureInformation{{
+			Label: "view(string $view)",
+			Parameters: []protocol.ParameterInformation{
+				{Label: protocol.ParameterLabel{Offsets: &[2]uint32{5, 17}}},
+				{Label: protocol.ParameterLabel{String: "string $view"}, Documentation: &protocol.StringOrMarkupContent{String: &documentation}},
+			},
+		}},
+	}
+
+	data, err := json.Marshal(help)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	expected := `{"signatures":[{"label":"view(string $view)","parameters":[{"label":[5,17]},{"label":"string $view","documentation":"The view name."}]}]}`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
+
+func Test_DocumentSignatureHelp_InvalidParameterLabel(t *testing.T) {
+	for _, input := range []string{`[1]`, `[1,2,3]`, `{"start":1}`, `[-1,2]`} {
+		var label protocol.ParameterLabel
+		if err := json.Unmarshal([]byte(input), &label); err == nil {
+			t.Fatalf("expected error for %s", input)
+		}
+	}
+}
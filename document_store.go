@@ -0,0 +1,259 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrDocumentNotFound is returned when a document is not open in a DocumentStore.
+	ErrDocumentNotFound = errors.New("document not found")
+
+	// ErrDocumentVersion is returned when a change does not increase the document version.
+	ErrDocumentVersion = errors.New("document version out of order")
+)
+
+// TextDocument - An immutable snapshot of an open text document.
+type TextDocument struct {
+	// URI is the unique resource identifier of the document.
+	URI DocumentURI
+
+	// LanguageID is the language identifier associated with the document.
+	LanguageID LanguageID
+
+	// Version is the version number of the document.
+	Version int
+
+	// Text is the content of the document.
+	Text string
+
+	// Encoding is the position encoding used to interpret the character
+	// offset of positions. Empty means UTF-16, the protocol default.
+	Encoding PositionEncodingKind
+
+	// byte offset of the start of each line.
+	lines []int
+}
+
+// NewTextDocument creates a document snapshot.
+func NewTextDocument(uri DocumentURI, languageID LanguageID, version int, text string) *TextDocument {
+	return &TextDocument{
+		URI:        uri,
+		LanguageID: languageID,
+		Version:    version,
+		Text:       text,
+		lines:      lineOffsets(text),
+	}
+}
+
+// LineCount returns the number of lines in the document.
+// A document always has at least one line.
+func (d *TextDocument) LineCount() int {
+	return len(d.lines)
+}
+
+// LineText returns the content of the given zero-based line without the line terminator.
+// It returns an empty string for lines past the end of the document.
+func (d *TextDocument) LineText(line uint32) string {
+	start, end := d.lineBounds(line)
+	return d.Text[start:end]
+}
+
+// OffsetAt converts a position to a byte offset into Text.
+//
+// Positions past the end of a line resolve to the end of that line and
+// positions past the last line resolve to the end of the document.
+func (d *TextDocument) OffsetAt(pos Position) int {
+	if int(pos.Line) >= len(d.lines) {
+		return len(d.Text)
+	}
+
+	start, end := d.lineBounds(pos.Line)
+	return start + d.Encoding.ByteOffset(d.Text[start:end], pos.Character)
+}
+
+// PositionAt converts a byte offset into Text to a position.
+//
+// Offsets are clamped to the document, and offsets inside a multi-byte
+// character or a line terminator resolve to its start.
+func (d *TextDocument) PositionAt(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(d.Text) {
+		offset = len(d.Text)
+	}
+
+	line := sort.Search(len(d.lines), func(i int) bool { return d.lines[i] > offset }) - 1
+	start, end := d.lineBounds(uint32(line))
+	return positionIn(d.Text, uint32(line), start, end, offset, d.Encoding)
+}
+
+// lineBounds returns the byte offsets of the start and end of a line,
+// excluding the line terminator.
+func (d *TextDocument) lineBounds(line uint32) (int, int) {
+	if int(line) >= len(d.lines) {
+		return len(d.Text), len(d.Text)
+	}
+
+	start := d.lines[line]
+	end := len(d.Text)
+	if int(line)+1 < len(d.lines) {
+		end = d.lines[line+1]
+	}
+
+	if end > start && d.Text[end-1] == '\n' {
+		end--
+	}
+	if end > start && d.Text[end-1] == '\r' {
+		end--
+	}
+
+	return start, end
+}
+
+// applyChanges returns a new snapshot with the content changes applied in order.
+func (d *TextDocument) applyChanges(version int, changes []TextDocumentContentChangeEvent) *TextDocument {
+	doc := d
+	for _, change := range changes {
+		text := change.Text
+		if change.Range != nil {
+			start := doc.OffsetAt(change.Range.Start)
+			end := doc.OffsetAt(change.Range.End)
+			if end < start {
+				start, end = end, start
+			}
+			text = doc.Text[:start] + change.Text + doc.Text[end:]
+		}
+		doc = NewTextDocument(d.URI, d.LanguageID, version, text)
+		doc.Encoding = d.Encoding
+	}
+
+	if doc == d {
+		doc = NewTextDocument(d.URI, d.LanguageID, version, d.Text)
+		doc.Encoding = d.Encoding
+	}
+
+	return doc
+}
+
+// lineOffsets returns the byte offset of the start of each line.
+// `\n`, `\r\n` and `\r` are all recognized as line terminators.
+func lineOffsets(text string) []int {
+	offsets := []int{0}
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\r':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				i++
+			}
+			offsets = append(offsets, i+1)
+		case '\n':
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// DocumentStore keeps track of open text documents by applying the
+// text document synchronization notifications.
+//
+// It is safe for concurrent use. Documents returned by the store are
+// immutable snapshots, so readers are never affected by later changes.
+type DocumentStore struct {
+	mu       sync.RWMutex
+	docs     map[DocumentURI]*TextDocument
+	encoding PositionEncodingKind
+}
+
+// NewDocumentStore creates an empty document store.
+func NewDocumentStore() *DocumentStore {
+	return &DocumentStore{
+		docs: make(map[DocumentURI]*TextDocument),
+	}
+}
+
+// SetPositionEncoding sets the position encoding negotiated with the client.
+// It applies to documents opened afterwards.
+func (s *DocumentStore) SetPositionEncoding(encoding PositionEncodingKind) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoding = encoding
+}
+
+// DidOpen adds the opened document to the store, replacing any previous
+// document with the same URI.
+func (s *DocumentStore) DidOpen(params DidOpenTextDocumentParams) *TextDocument {
+	item := params.TextDocument
+	doc := NewTextDocument(DocumentURI(item.URI), item.LanguageID, item.Version, item.Text)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	doc.Encoding = s.encoding
+	s.docs[doc.URI] = doc
+
+	return doc
+}
+
+// DidChange applies the content changes to the document in order.
+//
+// It returns ErrDocumentNotFound if the document is not open and
+// ErrDocumentVersion if the new version is not greater than the current one.
+func (s *DocumentStore) DidChange(params DidChangeTextDocumentParams) (*TextDocument, error) {
+	uri := DocumentURI(params.TextDocument.URI)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[uri]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDocumentNotFound, uri)
+	}
+
+	if params.TextDocument.Version <= doc.Version {
+		return nil, fmt.Errorf("%w: %s has version %d, got %d", ErrDocumentVersion, uri, doc.Version, params.TextDocument.Version)
+	}
+
+	doc = doc.applyChanges(params.TextDocument.Version, params.ContentChanges)
+	s.docs[uri] = doc
+
+	return doc, nil
+}
+
+// DidClose removes the document from the store.
+func (s *DocumentStore) DidClose(params DidCloseTextDocumentParams) error {
+	uri := DocumentURI(params.TextDocument.URI)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.docs[uri]; !ok {
+		return fmt.Errorf("%w: %s", ErrDocumentNotFound, uri)
+	}
+	delete(s.docs, uri)
+
+	return nil
+}
+
+// Get returns the current snapshot of an open document.
+func (s *DocumentStore) Get(uri DocumentURI) (*TextDocument, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc, ok := s.docs[uri]
+	return doc, ok
+}
+
+// URIs returns the URIs of all open documents.
+func (s *DocumentStore) URIs() []DocumentURI {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	uris := make([]DocumentURI, 0, len(s.docs))
+	for uri := range s.docs {
+		uris = append(uris, uri)
+	}
+	return uris
+}
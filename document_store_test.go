@@ -0,0 +1,219 @@
+package protocol_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func openDocument(t *testing.T, store *protocol.DocumentStore, text string) *protocol.TextDocument {
+	t.Helper()
+
+	return store.DidOpen(protocol.DidOpenTextDocumentParams{
+		TextDocument: protocol.TextDocumentItem{
+			URI:        "file:///tmp/view.blade.php",
+			LanguageID: protocol.LanguageBlade,
+			Version:    1,
+			Text:       text,
+		},
+	})
+}
+
+func changeParams(version int, changes ...protocol.TextDocumentContentChangeEvent) protocol.DidChangeTextDocumentParams {
+	return protocol.DidChangeTextDocumentParams{
+		TextDocument: protocol.VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: "file:///tmp/view.blade.php"},
+			Version:                version,
+		},
+		ContentChanges: changes,
+	}
+}
+
+func rangeChange(startLine, startChar, endLine, endChar uint32, text string) protocol.TextDocumentContentChangeEvent {
+	return protocol.TextDocumentContentChangeEvent{
+		Range: &protocol.Range{
+			Start: protocol.Position{Line: startLine, Character: startChar},
+			End:   protocol.Position{Line: endLine, Character: endChar},
+		},
+		Text: text,
+	}
+}
+
+func Test_DocumentStore_LineLookups(t *testing.T) {
+	doc := protocol.NewTextDocument("file:///a", protocol.LanguagePHP, 1, "one\r\ntwo\nthree\rfour")
+
+	if doc.LineCount() != 4 {
+		t.Fatalf("expected 4 lines, got %d", doc.LineCount())
+	}
+
+	for i, want := range []string{"one", "two", "three", "four", ""} {
+		if got := doc.LineText(uint32(i)); got != want {
+			t.Fatalf("line %d: expected %q, got %q", i, want, got)
+		}
+	}
+
+	if offset := doc.OffsetAt(protocol.Position{Line: 1, Character: 1}); offset != 6 {
+		t.Fatalf("expected offset 6, got %d", offset)
+	}
+
+	if offset := doc.OffsetAt(protocol.Position{Line: 0, Character: 100}); offset != 3 {
+		t.Fatalf("expected position past line end to clamp to 3, got %d", offset)
+	}
+
+	if offset := doc.OffsetAt(protocol.Position{Line: 10}); offset != len(doc.Text) {
+		t.Fatalf("expected position past last line to clamp to %d, got %d", len(doc.Text), offset)
+	}
+
+	if pos := doc.PositionAt(15); pos.Line != 3 || pos.Character != 0 {
+		t.Fatalf("expected 3:0, got %+v", pos)
+	}
+
+	if pos := doc.PositionAt(4); pos.Line != 0 || pos.Character != 3 {
+		t.Fatalf("expected offset inside CRLF to resolve to 0:3, got %+v", pos)
+	}
+}
+
+func Test_DocumentStore_UTF16Positions(t *testing.T) {
+	// "é" is 2 bytes and 1 UTF-16 unit, "😀" is 4 bytes and 2 UTF-16 units.
+	doc := protocol.NewTextDocument("file:///a", protocol.LanguagePHP, 1, "é😀x")
+
+	if offset := doc.OffsetAt(protocol.Position{Character: 3}); offset != 6 {
+		t.Fatalf("expected offset 6, got %d", offset)
+	}
+
+	if offset := doc.OffsetAt(protocol.Position{Character: 2}); offset != 2 {
+		t.Fatalf("expected offset inside surrogate pair to resolve to 2, got %d", offset)
+	}
+
+	if pos := doc.PositionAt(6); pos.Character != 3 {
+		t.Fatalf("expected character 3, got %+v", pos)
+	}
+
+	if pos := doc.PositionAt(4); pos.Character != 1 {
+		t.Fatalf("expected offset inside rune to resolve to character 1, got %+v", pos)
+	}
+}
+
+func Test_DocumentStore_AppliesChangesInOrder(t *testing.T) {
+	store := protocol.NewDocumentStore()
+	openDocument(t, store, "@section('a')\n@endsection\n")
+
+	doc, err := store.DidChange(changeParams(2,
+		rangeChange(0, 10, 0, 11, "content"),
+		rangeChange(1, 0, 1, 0, "  <p>hi</p>\n"),
+		rangeChange(2, 11, 2, 11, "\n@stop"),
+	))
+	if err != nil {
+		t.Fatalf("change failed: %v", err)
+	}
+
+	want := "@section('content')\n  <p>hi</p>\n@endsection\n@stop\n"
+	if doc.Text != want {
+		t.Fatalf("expected %q, got %q", want, doc.Text)
+	}
+
+	if doc.Version != 2 || doc.LanguageID != protocol.LanguageBlade {
+		t.Fatalf("unexpected document metadata: %+v", doc)
+	}
+
+	if current, ok := store.Get("file:///tmp/view.blade.php"); !ok || current != doc {
+		t.Fatalf("expected store to return the changed document")
+	}
+}
+
+func Test_DocumentStore_FullContentChange(t *testing.T) {
+	store := protocol.NewDocumentStore()
+	openDocument(t, store, "old")
+
+	doc, err := store.DidChange(changeParams(5,
+		rangeChange(0, 0, 0, 3, "older"),
+		protocol.TextDocumentContentChangeEvent{Text: "new\ntext"},
+		rangeChange(1, 0, 1, 4, "content"),
+	))
+	if err != nil {
+		t.Fatalf("change failed: %v", err)
+	}
+
+	if doc.Text != "new\ncontent" {
+		t.Fatalf("unexpected text %q", doc.Text)
+	}
+}
+
+func Test_DocumentStore_RejectsOutOfOrderVersions(t *testing.T) {
+	store := protocol.NewDocumentStore()
+	openDocument(t, store, "text")
+
+	if _, err := store.DidChange(changeParams(3, protocol.TextDocumentContentChangeEvent{Text: "v3"})); err != nil {
+		t.Fatalf("change failed: %v", err)
+	}
+
+	for _, version := range []int{3, 2} {
+		if _, err := store.DidChange(changeParams(version, protocol.TextDocumentContentChangeEvent{Text: "stale"})); !errors.Is(err, protocol.ErrDocumentVersion) {
+			t.Fatalf("version %d: expected ErrDocumentVersion, got %v", version, err)
+		}
+	}
+
+	if doc, _ := store.Get("file:///tmp/view.blade.php"); doc.Text != "v3" {
+		t.Fatalf("expected rejected changes not to be applied, got %q", doc.Text)
+	}
+}
+
+func Test_DocumentStore_Close(t *testing.T) {
+	store := protocol.NewDocumentStore()
+	openDocument(t, store, "text")
+
+	closeParams := protocol.DidCloseTextDocumentParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: "file:///tmp/view.blade.php"},
+	}
+
+	if err := store.DidClose(closeParams); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if _, ok := store.Get("file:///tmp/view.blade.php"); ok {
+		t.Fatalf("expected document to be removed")
+	}
+
+	if err := store.DidClose(closeParams); !errors.Is(err, protocol.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound, got %v", err)
+	}
+
+	if _, err := store.DidChange(changeParams(2)); !errors.Is(err, protocol.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound, got %v", err)
+	}
+}
+
+func Test_DocumentStore_ConcurrentReaders(t *testing.T) {
+	store := protocol.NewDocumentStore()
+	openDocument(t, store, "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				doc, ok := store.Get("file:///tmp/view.blade.php")
+				if !ok {
+					t.Errorf("document missing")
+					return
+				}
+				if strings.Count(doc.Text, "x") != doc.Version-1 {
+					t.Errorf("inconsistent snapshot: version %d text %q", doc.Version, doc.Text)
+					return
+				}
+			}
+		}()
+	}
+
+	for version := 2; version <= 100; version++ {
+		if _, err := store.DidChange(changeParams(version, rangeChange(0, 0, 0, 0, "x"))); err != nil {
+			t.Fatalf("change failed: %v", err)
+		}
+	}
+
+	wg.Wait()
+}
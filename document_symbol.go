@@ -0,0 +1,230 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// MethodTextDocumentDocumentSymbol method name of "textDocument/documentSymbol".
+	MethodTextDocumentDocumentSymbol = "textDocument/documentSymbol"
+)
+
+// DocumentSymbolParams defines the parameters for a textDocument/documentSymbol request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentSymbolParams
+type DocumentSymbolParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// The text document.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// SymbolKind - A symbol kind.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#symbolKind
+type SymbolKind int
+
+const (
+	SymbolKindFile          SymbolKind = 1
+	SymbolKindModule        SymbolKind = 2
+	SymbolKindNamespace     SymbolKind = 3
+	SymbolKindPackage       SymbolKind = 4
+	SymbolKindClass         SymbolKind = 5
+	SymbolKindMethod        SymbolKind = 6
+	SymbolKindProperty      SymbolKind = 7
+	SymbolKindField         SymbolKind = 8
+	SymbolKindConstructor   SymbolKind = 9
+	SymbolKindEnum          SymbolKind = 10
+	SymbolKindInterface     SymbolKind = 11
+	SymbolKindFunction      SymbolKind = 12
+	SymbolKindVariable      SymbolKind = 13
+	SymbolKindConstant      SymbolKind = 14
+	SymbolKindString        SymbolKind = 15
+	SymbolKindNumber        SymbolKind = 16
+	SymbolKindBoolean       SymbolKind = 17
+	SymbolKindArray         SymbolKind = 18
+	SymbolKindObject        SymbolKind = 19
+	SymbolKindKey           SymbolKind = 20
+	SymbolKindNull          SymbolKind = 21
+	SymbolKindEnumMember    SymbolKind = 22
+	SymbolKindStruct        SymbolKind = 23
+	SymbolKindEvent         SymbolKind = 24
+	SymbolKindOperator      SymbolKind = 25
+	SymbolKindTypeParameter SymbolKind = 26
+)
+
+// SymbolTag - Symbol tags are extra annotations that tweak the rendering of a symbol.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#symbolTag
+//
+// @since 3.16.0
+type SymbolTag int
+
+const (
+	// Render a symbol as obsolete, usually using a strike-out.
+	SymbolTagDeprecated SymbolTag = 1
+)
+
+// DocumentSymbol - Represents programming constructs like variables, classes,
+// interfaces etc. that appear in a document. Document symbols can be
+// hierarchical and they have two ranges: one that encloses its definition and
+// one that points to its most interesting range, e.g. the range of an
+// identifier.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#documentSymbol
+type DocumentSymbol struct {
+	// The name of this symbol. Will be displayed in the user interface and
+	// therefore must not be an empty string or a string only consisting of
+	// white spaces.
+	Name string `json:"name"`
+
+	// More detail for this symbol, e.g the signature of a function.
+	Detail string `json:"detail,omitempty"`
+
+	// The kind of this symbol.
+	Kind SymbolKind `json:"kind"`
+
+	// Tags for this document symbol.
+	//
+	// @since 3.16.0
+	Tags []SymbolTag `json:"tags,omitempty"`
+
+	// Indicates if this symbol is deprecated.
+	//
+	// Deprecated: Use tags instead.
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// The range enclosing this symbol not including leading/trailing
+	// whitespace but everything else like comments. This information is
+	// typically used to determine if the clients cursor is inside the symbol
+	// to reveal in the symbol in the UI.
+	Range Range `json:"range"`
+
+	// The range that should be selected and revealed when this symbol is being
+	// picked, e.g. the name of a function. Must be contained by the `range`.
+	SelectionRange Range `json:"selectionRange"`
+
+	// Children of this symbol, e.g. properties of a class.
+	Children []DocumentSymbol `json:"children,omitempty"`
+}
+
+// SymbolInformation - Represents information about programming constructs
+// like variables, classes, interfaces etc.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#symbolInformation
+type SymbolInformation struct {
+	// The name of this symbol.
+	Name string `json:"name"`
+
+	// The kind of this symbol.
+	Kind SymbolKind `json:"kind"`
+
+	// Tags for this symbol.
+	//
+	// @since 3.16.0
+	Tags []SymbolTag `json:"tags,omitempty"`
+
+	// Indicates if this symbol is deprecated.
+	//
+	// Deprecated: Use tags instead.
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// The location of this symbol. The location's range is used by a tool
+	// to reveal the location in the editor. If the symbol is selected in the
+	// tool the range's start information is used to position the cursor. So
+	// the range usually spans more than the actual symbol's name and does
+	// normally include things like visibility modifiers.
+	Location Location `json:"location"`
+
+	// The name of the symbol containing this symbol. This information is for
+	// user interface purposes (e.g. to render a qualifier in the user interface
+	// if necessary). It can't be used to re-infer a hierarchy for the document
+	// symbols.
+	ContainerName string `json:"containerName,omitempty"`
+}
+
+// DocumentSymbolResponse represents the result of a textDocument/documentSymbol request.
+//
+// It can be a slice of DocumentSymbols, a slice of SymbolInformation or null.
+// DocumentSymbols may only be returned if the client announced
+// `hierarchicalDocumentSymbolSupport`; see FlattenDocumentSymbols otherwise.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_documentSymbol
+type DocumentSymbolResponse struct {
+	DocumentSymbols   []DocumentSymbol
+	SymbolInformation []SymbolInformation
+	Null              bool
+}
+
+func (r DocumentSymbolResponse) MarshalJSON() ([]byte, error) {
+	if r.DocumentSymbols != nil {
+		return json.Marshal(r.DocumentSymbols)
+	}
+	if r.SymbolInformation != nil {
+		return json.Marshal(r.SymbolInformation)
+	}
+	return []byte("null"), nil
+}
+
+func (r *DocumentSymbolResponse) UnmarshalJSON(data []byte) error {
+	// Make sure object is reset.
+	*r = DocumentSymbolResponse{}
+
+	if string(data) == "null" {
+		r.Null = true
+		return nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return errors.New("invalid document symbol response: not null, []DocumentSymbol or []SymbolInformation")
+	}
+
+	// SymbolInformation carries a location, DocumentSymbol a range.
+	var probe struct {
+		Location *json.RawMessage `json:"location"`
+	}
+	if len(items) > 0 && json.Unmarshal(items[0], &probe) == nil && probe.Location != nil {
+		var infos []SymbolInformation
+		if err := json.Unmarshal(data, &infos); err != nil {
+			return err
+		}
+		r.SymbolInformation = infos
+		return nil
+	}
+
+	var symbols []DocumentSymbol
+	if err := json.Unmarshal(data, &symbols); err != nil {
+		return err
+	}
+	r.DocumentSymbols = symbols
+	return nil
+}
+
+// FlattenDocumentSymbols converts a hierarchy of document symbols of the
+// document uri into a flat list of SymbolInformation, for clients without
+// `hierarchicalDocumentSymbolSupport`.
+//
+// Symbols are listed parent first, with the name of the parent as the
+// container name of its children.
+func FlattenDocumentSymbols(uri DocumentURI, symbols []DocumentSymbol) []SymbolInformation {
+	result := []SymbolInformation{}
+	var walk func(symbols []DocumentSymbol, container string)
+	walk = func(symbols []DocumentSymbol, container string) {
+		for _, symbol := range symbols {
+			result = append(result, SymbolInformation{
+				Name:          symbol.Name,
+				Kind:          symbol.Kind,
+				Tags:          symbol.Tags,
+				Deprecated:    symbol.Deprecated,
+				Location:      Location{URI: uri, Range: symbol.Range},
+				ContainerName: container,
+			})
+			walk(symbol.Children, symbol.Name)
+		}
+	}
+	walk(symbols, "")
+	return result
+}
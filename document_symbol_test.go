@@ -0,0 +1,182 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func symbolRange(start, end uint32) protocol.Range {
+	return protocol.Range{
+		Start: protocol.Position{Line: start},
+		End:   protocol.Position{Line: end},
+	}
+}
+
+func Test_DocumentSymbol_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.DocumentSymbolParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///app/Models/User.php"},"partialResultToken":"symbols"}`), &params); err != nil {
+		t.Fatalf("unmarshal DocumentSymbolParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///app/Models/User.php" || params.PartialResultToken == nil {
+		t.Fatalf("unexpected DocumentSymbolParams: %+v", params)
+	}
+}
+
+func Test_DocumentSymbol_ResponseUnmarshalHierarchical(t *testing.T) {
+	data := []byte(`[{
+		"name": "User",
+		"kind": 5,
+		"range": {"start": {"line": 0, "character": 0}, "end": {"line": 20, "character": 1}},
+		"selectionRange": {"start": {"line": 0, "character": 6}, "end": {"line": 0, "character": 10}},
+		"children": [{
+			"name": "posts",
+			"detail": "HasMany",
+			"kind": 6,
+			"tags": [1],
+			"range": {"start": {"line": 5, "character": 4}, "end": {"line": 8, "character": 5}},
+			"selectionRange": {"start": {"line": 5, "character": 20}, "end": {"line": 5, "character": 25}}
+		}]
+	}]`)
+
+	var response protocol.DocumentSymbolResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if len(response.DocumentSymbols) != 1 || response.SymbolInformation != nil {
+		t.Fatalf("expected document symbols, got %+v", response)
+	}
+	class := response.DocumentSymbols[0]
+	if class.Kind != protocol.SymbolKindClass || len(class.Children) != 1 {
+		t.Fatalf("unexpected class symbol: %+v", class)
+	}
+	method := class.Children[0]
+	if method.Kind != protocol.SymbolKindMethod || method.Detail != "HasMany" || len(method.Tags) != 1 || method.Tags[0] != protocol.SymbolTagDeprecated {
+		t.Fatalf("unexpected method symbol: %+v", method)
+	}
+}
+
+func Test_DocumentSymbol_ResponseUnmarshalFlat(t *testing.T) {
+	data := []byte(`[{
+		"name": "posts",
+		"kind": 6,
+		"location": {"uri": "file:///app/Models/User.php", "range": {"start": {"line": 5, "character": 4}, "end": {"line": 8, "character": 5}}},
+		"containerName": "User"
+	}]`)
+
+	var response protocol.DocumentSymbolResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if len(response.SymbolInformation) != 1 || response.DocumentSymbols != nil {
+		t.Fatalf("expected symbol information, got %+v", response)
+	}
+	if info := response.SymbolInformation[0]; info.ContainerName != "User" || info.Location.URI != "file:///app/Models/User.php" {
+		t.Fatalf("unexpected symbol information: %+v", info)
+	}
+}
+
+func Test_DocumentSymbol_ResponseNullAndEmpty(t *testing.T) {
+	var response protocol.DocumentSymbolResponse
+	if err := json.Unmarshal([]byte(`null`), &response); err != nil || !response.Null {
+		t.Fatalf("expected null response, got %+v, %v", response, err)
+	}
+
+	if err := json.Unmarshal([]byte(`[]`), &response); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != `[]` {
+		t.Fatalf("expected empty array, got %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name":"User"}`), &response); err == nil {
+		t.Fatalf("expected error for object response")
+	}
+}
+
+func Test_DocumentSymbol_Flatten(t *testing.T) {
+	symbols := []protocol.DocumentSymbol{
+		{
+			Name:  "User",
+			Kind:  protocol.SymbolKindClass,
+			Range: symbolRange(0, 20),
+			Children: []protocol.DocumentSymbol{
+				{Name: "posts", Kind: protocol.SymbolKindMethod, Range: symbolRange(5, 8), Tags: []protocol.SymbolTag{protocol.SymbolTagDeprecated}},
+				{
+					Name:  "casts",
+					Kind:  protocol.SymbolKindMethod,
+					Range: symbolRange(10, 15),
+					Children: []protocol.DocumentSymbol{
+						{Name: "$attributes", Kind: protocol.SymbolKindVariable, Range: symbolRange(11, 11)},
+					},
+				},
+			},
+		},
+		{Name: "helper", Kind: protocol.SymbolKindFunction, Range: symbolRange(22, 24)},
+	}
+
+	flat := protocol.FlattenDocumentSymbols("file:///app/Models/User.php", symbols)
+
+	expected := []struct {
+		name      string
+		container string
+		line      uint32
+	}{
+		{"User", "", 0},
+		{"posts", "User", 5},
+		{"casts", "User", 10},
+		{"$attributes", "casts", 11},
+		{"helper", "", 22},
+	}
+
+	if len(flat) != len(expected) {
+		t.Fatalf("expected %d symbols, got %d", len(expected), len(flat))
+	}
+	for i, e := range expected {
+		info := flat[i]
+		if info.Name != e.name || info.ContainerName != e.container || info.Location.Range.Start.Line != e.line {
+			t.Fatalf("symbol %d: expected %+v, got %+v", i, e, info)
+		}
+		if info.Location.URI != "file:///app/Models/User.php" {
+			t.Fatalf("symbol %d: unexpected uri %q", i, info.Location.URI)
+		}
+	}
+	if len(flat[1].Tags) != 1 {
+		t.Fatalf("expected tags to be kept, got %+v", flat[1])
+	}
+
+	if flat := protocol.FlattenDocumentSymbols("file:///a.php", nil); flat == nil || len(flat) != 0 {
+		t.Fatalf("expected empty non-nil slice, got %#v", flat)
+	}
+}
+
+func Test_DocumentSymbol_ClientValueSets(t *testing.T) {
+	support := newClientSupport(t, `{
+		"textDocument": {"documentSymbol": {"symbolKind": {"valueSet": [5, 6]}, "tagSupport": {"valueSet": [1]}}},
+		"workspace": {"symbol": {"symbolKind": {"valueSet": [12]}, "tagSupport": {"valueSet": [1]}}}
+	}`)
+	caps := support.Capabilities()
+
+	documentSymbol := caps.TextDocument.DocumentSymbol
+	if kinds := documentSymbol.SymbolKind.ValueSet; len(kinds) != 2 || kinds[0] != protocol.SymbolKindClass || kinds[1] != protocol.SymbolKindMethod {
+		t.Fatalf("unexpected document symbol kinds: %v", kinds)
+	}
+	if tags := documentSymbol.TagSupport.ValueSet; len(tags) != 1 || tags[0] != protocol.SymbolTagDeprecated {
+		t.Fatalf("unexpected document symbol tags: %v", tags)
+	}
+
+	workspaceSymbol := caps.Workspace.Symbol
+	if kinds := workspaceSymbol.SymbolKind.ValueSet; len(kinds) != 1 || kinds[0] != protocol.SymbolKindFunction {
+		t.Fatalf("unexpected workspace symbol kinds: %v", kinds)
+	}
+	if tags := workspaceSymbol.TagSupport.ValueSet; len(tags) != 1 || tags[0] != protocol.SymbolTagDeprecated {
+		t.Fatalf("unexpected workspace symbol tags: %v", tags)
+	}
+}
@@ -0,0 +1,22 @@
+package protocol
+
+const (
+	// MethodTextDocumentTypeDefinition method name of "textDocument/typeDefinition".
+	MethodTextDocumentTypeDefinition = "textDocument/typeDefinition"
+)
+
+// TypeDefinitionParams defines the parameters for a textDocument/typeDefinition request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#typeDefinitionParams
+//
+// @since 3.6.0
+type TypeDefinitionParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocumentPositionParams
+}
+
+// TypeDefinitionResponse represents the result of a textDocument/typeDefinition request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_typeDefinition
+type TypeDefinitionResponse = LocationResult
@@ -0,0 +1,32 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DocumentTypeDefinition_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.TypeDefinitionParams
+	if err := json.Unmarshal([]byte(`{"textDocument":{"uri":"file:///app/Models/Post.php"},"position":{"line":12,"character":20}}`), &params); err != nil {
+		t.Fatalf("unmarshal TypeDefinitionParams failed: %v", err)
+	}
+	if params.TextDocument.URI != "file:///app/Models/Post.php" || params.Position.Character != 20 {
+		t.Fatalf("unexpected TypeDefinitionParams: %+v", params)
+	}
+}
+
+func Test_DocumentTypeDefinition_ResponseMarshalLocation(t *testing.T) {
+	response := protocol.TypeDefinitionResponse{
+		Location: &protocol.Location{URI: "file:///app/Models/User.php"},
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != `{"uri":"file:///app/Models/User.php","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}` {
+		t.Fatalf("unexpected payload: %s", data)
+	}
+}
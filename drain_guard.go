@@ -0,0 +1,93 @@
+package protocol
+
+import (
+	"context"
+	"sync"
+)
+
+// DrainGuard is a Handler that tracks in-flight requests so a server can
+// stop gracefully.
+//
+// Once Drain is called, new requests fail with RPCRequestFailed while
+// notifications are still passed on, so that `$/cancelRequest` and `exit`
+// keep working during the drain.
+type DrainGuard struct {
+	next Handler
+
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{}
+}
+
+// NewDrainGuard creates a drain guard in front of next.
+func NewDrainGuard(next Handler) *DrainGuard {
+	return &DrainGuard{next: next}
+}
+
+// InFlight returns the number of requests currently being handled.
+func (d *DrainGuard) InFlight() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.inFlight
+}
+
+// Draining reports whether Drain was called.
+func (d *DrainGuard) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.draining
+}
+
+func (d *DrainGuard) Handle(ctx context.Context, req *Request) (any, error) {
+	if req.IsNotification() {
+		return d.next.Handle(ctx, req)
+	}
+
+	d.mu.Lock()
+	if d.draining {
+		d.mu.Unlock()
+		return nil, NewResponseError(RPCRequestFailed, "server is shutting down")
+	}
+	d.inFlight++
+	d.mu.Unlock()
+
+	defer d.done()
+	return d.next.Handle(ctx, req)
+}
+
+// Drain stops accepting new requests and waits for the in-flight ones to
+// finish. It returns the context error if ctx is done first.
+func (d *DrainGuard) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	if d.inFlight == 0 {
+		d.mu.Unlock()
+		return nil
+	}
+	if d.idle == nil {
+		d.idle = make(chan struct{})
+	}
+	idle := d.idle
+	d.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *DrainGuard) done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inFlight--
+	if d.inFlight == 0 && d.idle != nil {
+		close(d.idle)
+		d.idle = nil
+	}
+}
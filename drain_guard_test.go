@@ -0,0 +1,99 @@
+package protocol_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_DrainGuard_WaitsForInFlight(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	guard := protocol.NewDrainGuard(protocol.HandlerFunc(func(ctx context.Context, req *protocol.Request) (any, error) {
+		if req.Method == "slow" {
+			close(started)
+			<-release
+		}
+		return "ok", nil
+	}))
+
+	go func() {
+		_, _ = guard.Handle(context.Background(), newRequest(1, "slow", `{}`))
+	}()
+	<-started
+
+	if guard.InFlight() != 1 {
+		t.Fatalf("expected 1 in-flight request, got %d", guard.InFlight())
+	}
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- guard.Drain(context.Background())
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for !guard.Draining() {
+		if time.Now().After(deadline) {
+			t.Fatalf("guard did not start draining")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := guard.Handle(context.Background(), newRequest(2, "fast", `{}`))
+	expectErrorCode(t, err, protocol.RPCRequestFailed)
+
+	if _, err := guard.Handle(context.Background(), newRequest(0, "exit", ``)); err != nil {
+		t.Fatalf("expected notifications to pass while draining, got %v", err)
+	}
+
+	select {
+	case err := <-drained:
+		t.Fatalf("drain returned before in-flight request finished: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Fatalf("drain failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("drain did not finish")
+	}
+}
+
+func Test_DrainGuard_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	guard := protocol.NewDrainGuard(protocol.HandlerFunc(func(ctx context.Context, req *protocol.Request) (any, error) {
+		close(started)
+		<-release
+		return nil, nil
+	}))
+
+	go func() {
+		_, _ = guard.Handle(context.Background(), newRequest(1, "slow", `{}`))
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := guard.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func Test_DrainGuard_DrainIdle(t *testing.T) {
+	guard := protocol.NewDrainGuard(protocol.NewRouter())
+
+	if err := guard.Drain(context.Background()); err != nil {
+		t.Fatalf("expected idle drain to succeed, got %v", err)
+	}
+}
@@ -44,42 +44,7 @@ const (
 // InlayHintTooltip can be a plain string or a MarkupContent object.
 //
 // @since 3.17.0
-type InlayHintTooltip struct {
-	String        *string
-	MarkupContent *MarkupContent
-}
-
-func (t InlayHintTooltip) MarshalJSON() ([]byte, error) {
-	if t.String != nil {
-		return json.Marshal(*t.String)
-	}
-	if t.MarkupContent != nil {
-		return json.Marshal(t.MarkupContent)
-	}
-	return []byte("null"), nil
-}
-
-func (t *InlayHintTooltip) UnmarshalJSON(data []byte) error {
-	*t = InlayHintTooltip{}
-
-	if string(data) == "null" {
-		return nil
-	}
-
-	var str string
-	if err := json.Unmarshal(data, &str); err == nil {
-		t.String = &str
-		return nil
-	}
-
-	var markup MarkupContent
-	if err := json.Unmarshal(data, &markup); err == nil && markup.Kind != "" {
-		t.MarkupContent = &markup
-		return nil
-	}
-
-	return errors.New("invalid InlayHintTooltip: not string, MarkupContent, or null")
-}
+type InlayHintTooltip = StringOrMarkupContent
 
 // InlayHintLabelPart - A segment of an inlay hint label.
 //
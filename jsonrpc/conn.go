@@ -0,0 +1,362 @@
+// Package jsonrpc implements JSON-RPC 2.0 over the framing used by the
+// Language Server Protocol base protocol.
+//
+// It is optional; the protocol package itself does not depend on it.
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/laravel-ls/protocol"
+)
+
+// ErrClosed is returned by calls on a connection that has been closed.
+var ErrClosed = errors.New("jsonrpc: connection closed")
+
+// Handler handles incoming requests and notifications.
+//
+// For notifications the returned result and error are discarded.
+type Handler interface {
+	Handle(ctx context.Context, conn *Conn, req *Request) (any, error)
+}
+
+// HandlerFunc is an adapter to allow the use of ordinary functions as handlers.
+type HandlerFunc func(ctx context.Context, conn *Conn, req *Request) (any, error)
+
+func (f HandlerFunc) Handle(ctx context.Context, conn *Conn, req *Request) (any, error) {
+	return f(ctx, conn, req)
+}
+
+// FromProtocol adapts a transport independent protocol.Handler, such as a
+// *protocol.Router, to a Handler.
+func FromProtocol(h protocol.Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, conn *Conn, req *Request) (any, error) {
+		return h.Handle(ctx, &protocol.Request{ID: req.ID, Method: req.Method, Params: req.Params})
+	})
+}
+
+type connContextKey struct{}
+
+// ConnFromContext returns the connection a request is being handled on,
+// or nil if ctx does not belong to a request handled by a Conn.
+func ConnFromContext(ctx context.Context) *Conn {
+	conn, _ := ctx.Value(connContextKey{}).(*Conn)
+	return conn
+}
+
+// Conn is a JSON-RPC connection to a peer. It can both serve incoming
+// requests and issue outgoing ones.
+//
+// Notifications are handled one at a time in the order they are received,
+// so that state changing notifications like `textDocument/didChange` are
+// observed in order. Requests are handled concurrently, each starting once
+// the notifications received before it were handled.
+//
+// Incoming messages are dispatched on a separate goroutine, so handlers,
+// including notification handlers, may Call the peer.
+type Conn struct {
+	stream  *Stream
+	handler Handler
+
+	mu      sync.Mutex
+	seq     int64
+	pending map[ID]chan *Response
+	closed  bool
+	err     error
+
+	queueMu sync.Mutex
+	queue   []*Request
+	queued  chan struct{}
+
+	done chan struct{}
+}
+
+// NewConn creates a connection over rwc and starts reading messages from it.
+// Incoming requests are passed to handler. A nil handler responds to every
+// request with a method not found error.
+//
+// The connection is closed when ctx is done.
+func NewConn(ctx context.Context, rwc io.ReadWriteCloser, handler Handler) *Conn {
+	c := &Conn{
+		stream:  NewStream(rwc),
+		handler: handler,
+		pending: make(map[ID]chan *Response),
+		queued:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	go c.readLoop(ctx)
+	go c.dispatchLoop(ctx)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-c.done:
+		}
+	}()
+
+	return c
+}
+
+// Call sends a request and waits for the response. If result is non-nil
+// the response result is decoded into it.
+//
+// If the peer responds with an error it is returned as a *protocol.ResponseError.
+func (c *Conn) Call(ctx context.Context, method string, params any, result any) error {
+	raw, err := marshalParams(params)
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan *Response, 1)
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	c.seq++
+	id := NumberID(c.seq)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.stream.WriteMessage(Request{ID: &id, Method: method, Params: raw}); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			return json.Unmarshal(resp.Result, result)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.done:
+		return ErrClosed
+	}
+}
+
+// Notify sends a notification. No response is expected.
+func (c *Conn) Notify(ctx context.Context, method string, params any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	raw, err := marshalParams(params)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	return c.stream.WriteMessage(Request{Method: method, Params: raw})
+}
+
+// Close closes the connection. Pending calls return ErrClosed.
+func (c *Conn) Close() error {
+	return c.close(ErrClosed)
+}
+
+// Done returns a channel that is closed when the connection is closed.
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason the connection was closed, or nil while it is open.
+// A peer closing the connection results in io.EOF.
+func (c *Conn) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *Conn) close(cause error) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.err = cause
+	c.mu.Unlock()
+
+	err := c.stream.Close()
+	close(c.done)
+	return err
+}
+
+func (c *Conn) readLoop(ctx context.Context) {
+	for {
+		data, err := c.stream.ReadMessage()
+		if err != nil {
+			c.close(err)
+			return
+		}
+		c.handleMessage(ctx, data)
+	}
+}
+
+func (c *Conn) handleMessage(ctx context.Context, data []byte) {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '[' {
+		c.reply(nil, nil, protocol.NewResponseError(protocol.RPCInvalidRequest, "batch requests are not supported"))
+		return
+	}
+
+	var msg wireMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.reply(nil, nil, protocol.NewResponseError(protocol.RPCParseError, "%s", err.Error()))
+		return
+	}
+
+	// Responses are never answered, not even invalid ones, as two peers
+	// would otherwise keep answering each other's error responses.
+	isResponse := msg.Method == "" && (msg.Result != nil || msg.Error != nil)
+
+	if msg.JSONRPC != Version {
+		if !isResponse && (msg.Method == "" || msg.ID != nil) {
+			c.reply(msg.ID, nil, protocol.NewResponseError(protocol.RPCInvalidRequest, "unsupported jsonrpc version %q", msg.JSONRPC))
+		}
+		return
+	}
+
+	switch {
+	case msg.Method != "":
+		c.enqueue(&Request{ID: msg.ID, Method: msg.Method, Params: msg.Params})
+	case isResponse:
+		// Error responses without an id, e.g. to a message the peer could
+		// not parse, cannot be matched to a call and are dropped.
+		if msg.ID != nil {
+			c.deliver(&Response{ID: msg.ID, Result: msg.Result, Error: msg.Error})
+		}
+	default:
+		c.reply(msg.ID, nil, protocol.NewResponseError(protocol.RPCInvalidRequest, "message is neither a request nor a response"))
+	}
+}
+
+// enqueue queues an incoming request or notification for the dispatch loop.
+// It never blocks, so the read loop can keep delivering responses.
+func (c *Conn) enqueue(req *Request) {
+	c.queueMu.Lock()
+	c.queue = append(c.queue, req)
+	c.queueMu.Unlock()
+
+	select {
+	case c.queued <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchLoop handles queued notifications in order and starts a goroutine
+// for every queued request.
+func (c *Conn) dispatchLoop(ctx context.Context) {
+	for {
+		select {
+		case <-c.queued:
+		case <-c.done:
+			return
+		}
+
+		c.queueMu.Lock()
+		queue := c.queue
+		c.queue = nil
+		c.queueMu.Unlock()
+
+		for _, req := range queue {
+			if req.IsNotification() {
+				c.handle(ctx, req)
+				continue
+			}
+			go c.handleRequest(ctx, req)
+		}
+	}
+}
+
+func (c *Conn) handle(ctx context.Context, req *Request) (any, error) {
+	if c.handler == nil {
+		return nil, protocol.NewResponseError(protocol.RPCMethodNotFound, "method %q not found", req.Method)
+	}
+	return c.handler.Handle(context.WithValue(ctx, connContextKey{}, c), c, req)
+}
+
+func (c *Conn) handleRequest(ctx context.Context, req *Request) {
+	result, err := c.handle(ctx, req)
+	if err != nil {
+		c.reply(req.ID, nil, ToResponseError(err))
+		return
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		c.reply(req.ID, nil, protocol.NewResponseError(protocol.RPCInternalError, "failed to encode result: %s", err.Error()))
+		return
+	}
+
+	c.reply(req.ID, raw, nil)
+}
+
+func (c *Conn) reply(id *ID, result json.RawMessage, rerr *protocol.ResponseError) {
+	// A write error means the connection is broken, which the read loop
+	// will notice as well.
+	_ = c.stream.WriteMessage(Response{ID: id, Result: result, Error: rerr})
+}
+
+func (c *Conn) deliver(resp *Response) {
+	c.mu.Lock()
+	ch, ok := c.pending[*resp.ID]
+	delete(c.pending, *resp.ID)
+	c.mu.Unlock()
+
+	if ok {
+		ch <- resp
+	}
+}
+
+// ToResponseError converts a handler error to the error object sent to the peer.
+//
+// A *protocol.ResponseError anywhere in the chain is used as is, a
+// context.Canceled error maps to RPCRequestCancelled and any other error
+// maps to RPCRequestFailed.
+func ToResponseError(err error) *protocol.ResponseError {
+	var rerr *protocol.ResponseError
+	if errors.As(err, &rerr) {
+		return rerr
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return protocol.NewResponseError(protocol.RPCRequestCancelled, "%s", err.Error())
+	}
+
+	return protocol.NewResponseError(protocol.RPCRequestFailed, "%s", err.Error())
+}
+
+func marshalParams(params any) (json.RawMessage, error) {
+	if params == nil {
+		return nil, nil
+	}
+	if raw, ok := params.(json.RawMessage); ok {
+		return raw, nil
+	}
+	return json.Marshal(params)
+}
@@ -0,0 +1,464 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/laravel-ls/protocol"
+	"github.com/laravel-ls/protocol/jsonrpc"
+)
+
+func newConnPair(t *testing.T, handler jsonrpc.Handler) (*jsonrpc.Conn, *jsonrpc.Stream) {
+	t.Helper()
+
+	server, client := net.Pipe()
+	conn := jsonrpc.NewConn(context.Background(), server, handler)
+	t.Cleanup(func() {
+		conn.Close()
+		client.Close()
+	})
+
+	return conn, jsonrpc.NewStream(client)
+}
+
+func readResponse(t *testing.T, stream *jsonrpc.Stream) map[string]any {
+	t.Helper()
+
+	data, err := stream.ReadMessage()
+	if err != nil {
+		t.Fatalf("read response failed: %v", err)
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	return resp
+}
+
+func errorCode(t *testing.T, resp map[string]any) int64 {
+	t.Helper()
+
+	obj, ok := resp["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected error response, got %v", resp)
+	}
+	return int64(obj["code"].(float64))
+}
+
+func Test_Conn_HandlesRequest(t *testing.T) {
+	_, peer := newConnPair(t, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		if req.Method != protocol.MethodInitialize {
+			return nil, protocol.NewResponseError(protocol.RPCMethodNotFound, "not found")
+		}
+		return protocol.InitializeResult{ServerInfo: &protocol.ServerInfo{Name: "test"}}, nil
+	}))
+
+	id := jsonrpc.StringID("init")
+	if err := peer.WriteMessage(jsonrpc.Request{ID: &id, Method: protocol.MethodInitialize, Params: json.RawMessage(`{}`)}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	resp := readResponse(t, peer)
+	if resp["id"] != "init" {
+		t.Fatalf("expected id init, got %v", resp["id"])
+	}
+
+	result, ok := resp["result"].(map[string]any)
+	if !ok || result["serverInfo"] == nil {
+		t.Fatalf("unexpected result: %v", resp)
+	}
+}
+
+func Test_Conn_MapsHandlerErrors(t *testing.T) {
+	_, peer := newConnPair(t, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		switch req.Method {
+		case "modified":
+			return nil, protocol.NewResponseError(protocol.RPCContentModified, "content modified")
+		case "cancelled":
+			return nil, context.Canceled
+		}
+		return nil, errors.New("boom")
+	}))
+
+	tests := map[string]int64{
+		"modified":  protocol.RPCContentModified,
+		"cancelled": protocol.RPCRequestCancelled,
+		"failed":    protocol.RPCRequestFailed,
+	}
+
+	var n int64
+	for method, code := range tests {
+		n++
+		id := jsonrpc.NumberID(n)
+		if err := peer.WriteMessage(jsonrpc.Request{ID: &id, Method: method}); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+
+		if got := errorCode(t, readResponse(t, peer)); got != code {
+			t.Fatalf("method %s: expected code %d, got %d", method, code, got)
+		}
+	}
+}
+
+func Test_Conn_NilHandlerReturnsMethodNotFound(t *testing.T) {
+	_, peer := newConnPair(t, nil)
+
+	id := jsonrpc.NumberID(1)
+	if err := peer.WriteMessage(jsonrpc.Request{ID: &id, Method: "unknown"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if got := errorCode(t, readResponse(t, peer)); got != protocol.RPCMethodNotFound {
+		t.Fatalf("expected method not found, got %d", got)
+	}
+}
+
+func Test_Conn_RejectsBatchAndInvalidMessages(t *testing.T) {
+	_, peer := newConnPair(t, nil)
+
+	tests := []struct {
+		message any
+		code    int64
+	}{
+		{json.RawMessage(`[{"jsonrpc":"2.0","id":1,"method":"a"}]`), protocol.RPCInvalidRequest},
+		{json.RawMessage(`{"jsonrpc":"1.0","id":1,"method":"a"}`), protocol.RPCInvalidRequest},
+		{json.RawMessage(`{"jsonrpc":"2.0"}`), protocol.RPCInvalidRequest},
+	}
+
+	for _, test := range tests {
+		if err := peer.WriteMessage(test.message); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+
+		if got := errorCode(t, readResponse(t, peer)); got != test.code {
+			t.Fatalf("message %s: expected code %d, got %d", test.message, test.code, got)
+		}
+	}
+}
+
+func Test_Conn_NotificationsAreNotAnswered(t *testing.T) {
+	received := make(chan string, 1)
+	_, peer := newConnPair(t, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		if req.IsNotification() {
+			received <- req.Method
+		}
+		return "ignored", nil
+	}))
+
+	if err := peer.WriteMessage(jsonrpc.Request{Method: protocol.MethodInitialized}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	select {
+	case method := <-received:
+		if method != protocol.MethodInitialized {
+			t.Fatalf("unexpected notification %s", method)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("notification was not handled")
+	}
+
+	// The next message read must be the response to this request, proving
+	// no response was sent for the notification.
+	id := jsonrpc.NumberID(9)
+	if err := peer.WriteMessage(jsonrpc.Request{ID: &id, Method: "ping"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if resp := readResponse(t, peer); resp["id"] != float64(9) {
+		t.Fatalf("expected response to request 9, got %v", resp)
+	}
+}
+
+func Test_Conn_CallAndNotify(t *testing.T) {
+	server, client := net.Pipe()
+
+	serverConn := jsonrpc.NewConn(context.Background(), server, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		if req.Method == "fail" {
+			return nil, protocol.NewResponseError(protocol.RPCServerNotInitialized, "not initialized")
+		}
+
+		var params protocol.HoverParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		if err := conn.Notify(ctx, protocol.MethodWindowLogMessage, protocol.LogMessageParams{
+			ShowMessageParams: protocol.ShowMessageParams{Type: protocol.MessageTypeLog, Message: "hover"},
+		}); err != nil {
+			return nil, err
+		}
+
+		return protocol.Position{Line: params.Position.Line + 1}, nil
+	}))
+	defer serverConn.Close()
+
+	logs := make(chan string, 1)
+	clientConn := jsonrpc.NewConn(context.Background(), client, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		logs <- req.Method
+		return nil, nil
+	}))
+	defer clientConn.Close()
+
+	var result protocol.Position
+	err := clientConn.Call(context.Background(), protocol.MethodTextDocumentHover, protocol.HoverParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{Position: protocol.Position{Line: 4}},
+	}, &result)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	if result.Line != 5 {
+		t.Fatalf("expected line 5, got %d", result.Line)
+	}
+
+	select {
+	case method := <-logs:
+		if method != protocol.MethodWindowLogMessage {
+			t.Fatalf("unexpected notification %s", method)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("notification was not received")
+	}
+
+	err = clientConn.Call(context.Background(), "fail", nil, nil)
+	var rerr *protocol.ResponseError
+	if !errors.As(err, &rerr) || rerr.Code != protocol.RPCServerNotInitialized {
+		t.Fatalf("expected server not initialized error, got %v", err)
+	}
+}
+
+func Test_Conn_CloseUnblocksPendingCalls(t *testing.T) {
+	server, client := net.Pipe()
+
+	// The server never answers.
+	block := make(chan struct{})
+	defer close(block)
+	serverConn := jsonrpc.NewConn(context.Background(), server, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		<-block
+		return nil, nil
+	}))
+	defer serverConn.Close()
+
+	clientConn := jsonrpc.NewConn(context.Background(), client, nil)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- clientConn.Call(context.Background(), "slow", nil, nil)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	clientConn.Close()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, jsonrpc.ErrClosed) {
+			t.Fatalf("expected ErrClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("pending call was not unblocked")
+	}
+
+	select {
+	case <-clientConn.Done():
+	default:
+		t.Fatalf("expected Done channel to be closed")
+	}
+}
+
+func Test_Conn_CallContextCancelled(t *testing.T) {
+	server, client := net.Pipe()
+
+	block := make(chan struct{})
+	defer close(block)
+	serverConn := jsonrpc.NewConn(context.Background(), server, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		<-block
+		return nil, nil
+	}))
+	defer serverConn.Close()
+
+	clientConn := jsonrpc.NewConn(context.Background(), client, nil)
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := clientConn.Call(ctx, "slow", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func Test_Conn_FromProtocolRouter(t *testing.T) {
+	router := protocol.NewRouter()
+	router.HandleInitialize(func(ctx context.Context, params protocol.InitializeParams) (protocol.InitializeResult, error) {
+		if jsonrpc.ConnFromContext(ctx) == nil {
+			t.Errorf("expected connection in handler context")
+		}
+		return protocol.InitializeResult{ServerInfo: &protocol.ServerInfo{Name: "router"}}, nil
+	})
+
+	_, peer := newConnPair(t, jsonrpc.FromProtocol(router))
+
+	id := jsonrpc.NumberID(1)
+	if err := peer.WriteMessage(jsonrpc.Request{ID: &id, Method: protocol.MethodInitialize, Params: json.RawMessage(`{"capabilities":{}}`)}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	resp := readResponse(t, peer)
+	result, ok := resp["result"].(map[string]any)
+	if !ok || result["serverInfo"].(map[string]any)["name"] != "router" {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+
+	id = jsonrpc.NumberID(2)
+	if err := peer.WriteMessage(jsonrpc.Request{ID: &id, Method: protocol.MethodTextDocumentHover}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if got := errorCode(t, readResponse(t, peer)); got != protocol.RPCMethodNotFound {
+		t.Fatalf("expected method not found, got %d", got)
+	}
+}
+
+func Test_Conn_DoesNotAnswerResponses(t *testing.T) {
+	// Connect two connections through a relay counting the messages
+	// exchanged between them.
+	left, leftRelay := net.Pipe()
+	right, rightRelay := net.Pipe()
+
+	leftConn := jsonrpc.NewConn(context.Background(), left, nil)
+	defer leftConn.Close()
+	rightConn := jsonrpc.NewConn(context.Background(), right, nil)
+	defer rightConn.Close()
+
+	leftStream := jsonrpc.NewStream(leftRelay)
+	rightStream := jsonrpc.NewStream(rightRelay)
+	defer leftStream.Close()
+	defer rightStream.Close()
+
+	var relayed atomic.Int64
+	relay := func(from, to *jsonrpc.Stream) {
+		for {
+			data, err := from.ReadMessage()
+			if err != nil {
+				return
+			}
+			relayed.Add(1)
+			if err := to.WriteMessage(data); err != nil {
+				return
+			}
+		}
+	}
+	go relay(leftStream, rightStream)
+	go relay(rightStream, leftStream)
+
+	go func() {
+		for _, message := range []string{
+			`{}`,
+			`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid"}}`,
+			`{"jsonrpc":"1.0","error":{"code":-32600,"message":"invalid"}}`,
+			`{"jsonrpc":"1.0","id":3,"result":null}`,
+			`{"jsonrpc":"2.0","id":4,"result":null}`,
+		} {
+			if err := leftStream.WriteMessage(json.RawMessage(message)); err != nil {
+				return
+			}
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	// Only the invalid `{}` message is answered, and the answer is not.
+	if got := relayed.Load(); got != 1 {
+		t.Fatalf("expected 1 relayed message, got %d", got)
+	}
+}
+
+func Test_Conn_NotificationHandlerCanCallPeer(t *testing.T) {
+	server, client := net.Pipe()
+
+	results := make(chan error, 1)
+	serverConn := jsonrpc.NewConn(context.Background(), server, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		if req.Method != protocol.MethodInitialized {
+			return nil, nil
+		}
+
+		var settings []string
+		err := conn.Call(ctx, "workspace/configuration", nil, &settings)
+		if err == nil && (len(settings) != 1 || settings[0] != "blade") {
+			err = errors.New("unexpected configuration result")
+		}
+		results <- err
+		return nil, nil
+	}))
+	defer serverConn.Close()
+
+	clientConn := jsonrpc.NewConn(context.Background(), client, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		return []string{"blade"}, nil
+	}))
+	defer clientConn.Close()
+
+	if err := clientConn.Notify(context.Background(), protocol.MethodInitialized, protocol.InitializedParams{}); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	select {
+	case err := <-results:
+		if err != nil {
+			t.Fatalf("call from notification handler failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("call from notification handler did not return")
+	}
+}
+
+func Test_Conn_RequestsStartAfterPrecedingNotifications(t *testing.T) {
+	var handled atomic.Bool
+	_, peer := newConnPair(t, jsonrpc.HandlerFunc(func(ctx context.Context, conn *jsonrpc.Conn, req *jsonrpc.Request) (any, error) {
+		if req.IsNotification() {
+			time.Sleep(20 * time.Millisecond)
+			handled.Store(true)
+			return nil, nil
+		}
+		return handled.Load(), nil
+	}))
+
+	if err := peer.WriteMessage(jsonrpc.Request{Method: protocol.MethodTextDocumentDidChange}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	id := jsonrpc.NumberID(1)
+	if err := peer.WriteMessage(jsonrpc.Request{ID: &id, Method: protocol.MethodTextDocumentHover}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if resp := readResponse(t, peer); resp["result"] != true {
+		t.Fatalf("expected request to observe the notification, got %v", resp)
+	}
+}
+
+func Test_Conn_ClosesOnOversizedMessage(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	conn := jsonrpc.NewConn(context.Background(), server, nil)
+	defer conn.Close()
+
+	go func() {
+		_, _ = client.Write([]byte("Content-Length: 9000000000000000000\r\n\r\n{}"))
+	}()
+
+	select {
+	case <-conn.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("connection was not closed")
+	}
+	if err := conn.Err(); !errors.Is(err, jsonrpc.ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+}
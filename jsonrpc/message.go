@@ -0,0 +1,98 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+
+	"github.com/laravel-ls/protocol"
+)
+
+// Version is the JSON-RPC protocol version carried by every message.
+const Version = "2.0"
+
+// ID - A request id. Can be a string or a number.
+type ID = protocol.RequestID
+
+// NumberID creates a numeric request id.
+func NumberID(n int64) ID {
+	return protocol.NumberRequestID(n)
+}
+
+// StringID creates a string request id.
+func StringID(s string) ID {
+	return protocol.StringRequestID(s)
+}
+
+// Request - A request or notification message.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#requestMessage
+type Request struct {
+	// The request id. Nil for notifications.
+	ID *ID
+
+	// The method to be invoked.
+	Method string
+
+	// The method's params.
+	Params json.RawMessage
+}
+
+// IsNotification reports whether the request is a notification,
+// meaning no response is expected.
+func (r *Request) IsNotification() bool {
+	return r.ID == nil
+}
+
+func (r Request) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      *ID             `json:"id,omitempty"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params,omitempty"`
+	}{Version, r.ID, r.Method, r.Params})
+}
+
+// Response - A response message sent as a result of a request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#responseMessage
+type Response struct {
+	// The request id. Nil if the id could not be determined.
+	ID *ID
+
+	// The result of a request. Ignored if Error is set.
+	Result json.RawMessage
+
+	// The error object in case a request fails.
+	Error *protocol.ResponseError
+}
+
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Error != nil {
+		return json.Marshal(struct {
+			JSONRPC string                  `json:"jsonrpc"`
+			ID      *ID                     `json:"id"`
+			Error   *protocol.ResponseError `json:"error"`
+		}{Version, r.ID, r.Error})
+	}
+
+	result := r.Result
+	if len(result) == 0 {
+		result = json.RawMessage("null")
+	}
+
+	return json.Marshal(struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      *ID             `json:"id"`
+		Result  json.RawMessage `json:"result"`
+	}{Version, r.ID, result})
+}
+
+// wireMessage is the union of all fields any message can carry,
+// used to classify an incoming message.
+type wireMessage struct {
+	JSONRPC string                  `json:"jsonrpc"`
+	ID      *ID                     `json:"id"`
+	Method  string                  `json:"method"`
+	Params  json.RawMessage         `json:"params"`
+	Result  json.RawMessage         `json:"result"`
+	Error   *protocol.ResponseError `json:"error"`
+}
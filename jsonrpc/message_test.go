@@ -0,0 +1,59 @@
+package jsonrpc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+	"github.com/laravel-ls/protocol/jsonrpc"
+)
+
+func Test_Message_IDMarshal(t *testing.T) {
+	data, err := json.Marshal(jsonrpc.StringID("7"))
+	if err != nil || string(data) != `"7"` {
+		t.Fatalf("expected \"7\", got %s (%v)", data, err)
+	}
+
+	data, err = json.Marshal(jsonrpc.NumberID(7))
+	if err != nil || string(data) != `7` {
+		t.Fatalf("expected 7, got %s (%v)", data, err)
+	}
+}
+
+func Test_Message_RequestMarshal(t *testing.T) {
+	id := jsonrpc.NumberID(1)
+	data, err := json.Marshal(jsonrpc.Request{ID: &id, Method: protocol.MethodInitialize, Params: json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatalf("marshal Request failed: %v", err)
+	}
+	if string(data) != `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}` {
+		t.Fatalf("unexpected request JSON: %s", data)
+	}
+
+	data, err = json.Marshal(jsonrpc.Request{Method: protocol.MethodInitialized})
+	if err != nil {
+		t.Fatalf("marshal notification failed: %v", err)
+	}
+	if string(data) != `{"jsonrpc":"2.0","method":"initialized"}` {
+		t.Fatalf("unexpected notification JSON: %s", data)
+	}
+}
+
+func Test_Message_ResponseMarshal(t *testing.T) {
+	id := jsonrpc.StringID("a")
+	data, err := json.Marshal(jsonrpc.Response{ID: &id})
+	if err != nil {
+		t.Fatalf("marshal Response failed: %v", err)
+	}
+	if string(data) != `{"jsonrpc":"2.0","id":"a","result":null}` {
+		t.Fatalf("unexpected response JSON: %s", data)
+	}
+
+	data, err = json.Marshal(jsonrpc.Response{Error: protocol.NewResponseError(protocol.RPCParseError, "bad")})
+	if err != nil {
+		t.Fatalf("marshal error Response failed: %v", err)
+	}
+	if string(data) != `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"bad"}}` {
+		t.Fatalf("unexpected error response JSON: %s", data)
+	}
+}
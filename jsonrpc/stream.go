@@ -0,0 +1,111 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxMessageSize is the largest message content, in bytes, a Stream reads.
+// Larger messages fail with ErrMessageTooLarge instead of being allocated.
+const MaxMessageSize = 64 << 20
+
+// ErrMessageTooLarge is returned when reading a message whose
+// `Content-Length` exceeds MaxMessageSize.
+var ErrMessageTooLarge = errors.New("jsonrpc: message too large")
+
+// Stream reads and writes messages framed by the LSP base protocol,
+// a header part containing `Content-Length` followed by the JSON content.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#baseProtocol
+type Stream struct {
+	rwc    io.ReadWriteCloser
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+}
+
+// NewStream creates a stream over the given connection.
+func NewStream(rwc io.ReadWriteCloser) *Stream {
+	return &Stream{
+		rwc:    rwc,
+		reader: bufio.NewReader(rwc),
+	}
+}
+
+// ReadMessage reads the content of the next message.
+//
+// ReadMessage must not be called concurrently.
+func (s *Stream) ReadMessage() (json.RawMessage, error) {
+	length := -1
+
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid header line: %q", line)
+		}
+
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || length < 0 {
+				return nil, fmt.Errorf("invalid Content-Length: %q", value)
+			}
+			if length > MaxMessageSize {
+				return nil, fmt.Errorf("%w: Content-Length %d exceeds %d bytes", ErrMessageTooLarge, length, MaxMessageSize)
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(s.reader, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// WriteMessage encodes v as JSON and writes it as a single message.
+//
+// WriteMessage is safe for concurrent use.
+func (s *Stream) WriteMessage(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, 0, len(data)+32)
+	frame = append(frame, "Content-Length: "...)
+	frame = strconv.AppendInt(frame, int64(len(data)), 10)
+	frame = append(frame, "\r\n\r\n"...)
+	frame = append(frame, data...)
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	_, err = s.rwc.Write(frame)
+	return err
+}
+
+// Close closes the underlying connection.
+func (s *Stream) Close() error {
+	return s.rwc.Close()
+}
@@ -0,0 +1,90 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/laravel-ls/protocol/jsonrpc"
+)
+
+type bufferConn struct {
+	io.Reader
+	bytes.Buffer
+}
+
+func (c *bufferConn) Read(p []byte) (int, error) {
+	return c.Reader.Read(p)
+}
+
+func (c *bufferConn) Close() error {
+	return nil
+}
+
+func Test_Stream_ReadMessage(t *testing.T) {
+	input := "Content-Length: 2\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n{}" +
+		"content-length:  4\r\n\r\nnull"
+
+	stream := jsonrpc.NewStream(&bufferConn{Reader: strings.NewReader(input)})
+
+	data, err := stream.ReadMessage()
+	if err != nil {
+		t.Fatalf("read first message failed: %v", err)
+	}
+	if string(data) != "{}" {
+		t.Fatalf("expected {}, got %q", data)
+	}
+
+	data, err = stream.ReadMessage()
+	if err != nil {
+		t.Fatalf("read second message failed: %v", err)
+	}
+	if string(data) != "null" {
+		t.Fatalf("expected null, got %q", data)
+	}
+
+	if _, err := stream.ReadMessage(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func Test_Stream_ReadMessageInvalidHeaders(t *testing.T) {
+	for _, input := range []string{
+		"Content-Type: application/json\r\n\r\n{}",
+		"Content-Length: abc\r\n\r\n{}",
+		"garbage\r\n\r\n{}",
+	} {
+		stream := jsonrpc.NewStream(&bufferConn{Reader: strings.NewReader(input)})
+		if _, err := stream.ReadMessage(); err == nil {
+			t.Fatalf("expected error for input %q", input)
+		}
+	}
+}
+
+func Test_Stream_ReadMessageTooLarge(t *testing.T) {
+	for _, input := range []string{
+		"Content-Length: 9000000000000000000\r\n\r\n{}",
+		"Content-Length: " + strconv.Itoa(jsonrpc.MaxMessageSize+1) + "\r\n\r\n{}",
+	} {
+		stream := jsonrpc.NewStream(&bufferConn{Reader: strings.NewReader(input)})
+		if _, err := stream.ReadMessage(); !errors.Is(err, jsonrpc.ErrMessageTooLarge) {
+			t.Fatalf("expected ErrMessageTooLarge for input %q, got %v", input, err)
+		}
+	}
+}
+
+func Test_Stream_WriteMessage(t *testing.T) {
+	conn := &bufferConn{Reader: strings.NewReader("")}
+	stream := jsonrpc.NewStream(conn)
+
+	if err := stream.WriteMessage(map[string]int{"a": 1}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if conn.String() != "Content-Length: 7\r\n\r\n{\"a\":1}" {
+		t.Fatalf("unexpected frame: %q", conn.String())
+	}
+}
@@ -1,12 +1,14 @@
 package protocol
 
-import "encoding/json"
-
 const (
 	MethodInitialize = "initialize"
 
 	MethodInitialized = "initialized"
 
+	MethodShutdown = "shutdown"
+
+	MethodExit = "exit"
+
 	MethodCancelRequest = "$/cancelRequest"
 )
 
@@ -69,11 +71,17 @@ type InitializeResult struct {
 	ServerInfo *ServerInfo `json:"serverInfo,omitempty"`
 }
 
+// InitializedParams - The parameters of the initialized notification.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#initialized
+type InitializedParams struct{}
+
 // CancelParams - Parameters for the cancel request
 //
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#cancelRequest
 type CancelParams struct {
-	Id json.Number `json:"id"`
+	// The request id to cancel.
+	Id RequestID `json:"id"`
 }
 
 // TraceValue - The LSP allows the client to control the tracing of the server.
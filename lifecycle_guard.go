@@ -0,0 +1,203 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// LifecycleState - The state of a server in the protocol lifecycle.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#lifeCycleMessages
+type LifecycleState int
+
+const (
+	// No `initialize` request has been answered yet.
+	LifecycleStateUninitialized LifecycleState = iota
+
+	// An `initialize` request is being handled.
+	LifecycleStateInitializing
+
+	// The `initialize` request was answered successfully.
+	LifecycleStateInitialized
+
+	// A `shutdown` request was received.
+	LifecycleStateShutdown
+
+	// An `exit` notification was received.
+	LifecycleStateExited
+)
+
+func (s LifecycleState) String() string {
+	switch s {
+	case LifecycleStateUninitialized:
+		return "uninitialized"
+	case LifecycleStateInitializing:
+		return "initializing"
+	case LifecycleStateInitialized:
+		return "initialized"
+	case LifecycleStateShutdown:
+		return "shutdown"
+	case LifecycleStateExited:
+		return "exited"
+	}
+	return "unknown"
+}
+
+// LifecycleGuard is a Handler that enforces the ordering rules of the
+// lifecycle messages before passing messages on to the next handler:
+//
+//   - Until `initialize` has been answered, requests fail with
+//     RPCServerNotInitialized and notifications other than `exit` are dropped.
+//   - `initialize` may only be sent once.
+//   - After `shutdown`, requests fail with RPCInvalidRequest and
+//     notifications other than `exit` are dropped.
+//   - On `exit` the guard is done, see Exited and ExitCode.
+//
+// The next handler does not need to handle `shutdown` and `exit`; if it
+// responds with RPCMethodNotFound for them the guard treats them as handled.
+type LifecycleGuard struct {
+	next Handler
+
+	mu       sync.Mutex
+	state    LifecycleState
+	exitCode int
+	exited   chan struct{}
+}
+
+// NewLifecycleGuard creates a guard in front of next.
+func NewLifecycleGuard(next Handler) *LifecycleGuard {
+	return &LifecycleGuard{
+		next:   next,
+		exited: make(chan struct{}),
+	}
+}
+
+// State returns the current lifecycle state.
+func (g *LifecycleGuard) State() LifecycleState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state
+}
+
+// Exited returns a channel that is closed when the `exit` notification is received.
+func (g *LifecycleGuard) Exited() <-chan struct{} {
+	return g.exited
+}
+
+// ExitCode returns the code the server process should exit with: 0 if
+// `shutdown` was received before `exit` and 1 otherwise.
+//
+// It is only meaningful once Exited is closed.
+func (g *LifecycleGuard) ExitCode() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.exitCode
+}
+
+func (g *LifecycleGuard) Handle(ctx context.Context, req *Request) (any, error) {
+	switch req.Method {
+	case MethodInitialize:
+		return g.initialize(ctx, req)
+	case MethodShutdown:
+		return g.shutdown(ctx, req)
+	case MethodExit:
+		return g.exit(ctx, req)
+	}
+
+	g.mu.Lock()
+	state := g.state
+	g.mu.Unlock()
+
+	switch state {
+	case LifecycleStateInitialized:
+		return g.next.Handle(ctx, req)
+	case LifecycleStateUninitialized, LifecycleStateInitializing:
+		if req.IsNotification() {
+			return nil, nil
+		}
+		return nil, NewResponseError(RPCServerNotInitialized, "server not initialized")
+	default:
+		if req.IsNotification() {
+			return nil, nil
+		}
+		return nil, NewResponseError(RPCInvalidRequest, "server is shutting down")
+	}
+}
+
+func (g *LifecycleGuard) initialize(ctx context.Context, req *Request) (any, error) {
+	g.mu.Lock()
+	if g.state != LifecycleStateUninitialized {
+		g.mu.Unlock()
+		return nil, NewResponseError(RPCInvalidRequest, "initialize may only be sent once")
+	}
+	g.state = LifecycleStateInitializing
+	g.mu.Unlock()
+
+	result, err := g.next.Handle(ctx, req)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// The state can only have moved on if `exit` arrived meanwhile.
+	if g.state == LifecycleStateInitializing {
+		if err != nil {
+			g.state = LifecycleStateUninitialized
+		} else {
+			g.state = LifecycleStateInitialized
+		}
+	}
+
+	return result, err
+}
+
+func (g *LifecycleGuard) shutdown(ctx context.Context, req *Request) (any, error) {
+	g.mu.Lock()
+	switch g.state {
+	case LifecycleStateUninitialized, LifecycleStateInitializing:
+		g.mu.Unlock()
+		return nil, NewResponseError(RPCServerNotInitialized, "server not initialized")
+	case LifecycleStateShutdown, LifecycleStateExited:
+		g.mu.Unlock()
+		return nil, NewResponseError(RPCInvalidRequest, "server is shutting down")
+	}
+	g.state = LifecycleStateShutdown
+	g.mu.Unlock()
+
+	result, err := g.next.Handle(ctx, req)
+	if isMethodNotFound(err) {
+		return json.RawMessage("null"), nil
+	}
+
+	return result, err
+}
+
+func (g *LifecycleGuard) exit(ctx context.Context, req *Request) (any, error) {
+	g.mu.Lock()
+	if g.state == LifecycleStateExited {
+		g.mu.Unlock()
+		return nil, nil
+	}
+
+	g.exitCode = 1
+	if g.state == LifecycleStateShutdown {
+		g.exitCode = 0
+	}
+	g.state = LifecycleStateExited
+	g.mu.Unlock()
+
+	defer close(g.exited)
+
+	_, err := g.next.Handle(ctx, req)
+	if isMethodNotFound(err) {
+		return nil, nil
+	}
+
+	return nil, err
+}
+
+func isMethodNotFound(err error) bool {
+	var rerr *ResponseError
+	return errors.As(err, &rerr) && rerr.Code == RPCMethodNotFound
+}
@@ -0,0 +1,206 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func newGuardedRouter(t *testing.T) (*protocol.LifecycleGuard, *[]string) {
+	t.Helper()
+
+	var calls []string
+	router := protocol.NewRouter()
+	router.HandleInitialize(func(ctx context.Context, params protocol.InitializeParams) (protocol.InitializeResult, error) {
+		calls = append(calls, protocol.MethodInitialize)
+		if params.RootPath == "fail" {
+			return protocol.InitializeResult{}, errors.New("failed")
+		}
+		return protocol.InitializeResult{}, nil
+	})
+	router.HandleInitialized(func(ctx context.Context, params protocol.InitializedParams) error {
+		calls = append(calls, protocol.MethodInitialized)
+		return nil
+	})
+	router.HandleHover(func(ctx context.Context, params protocol.HoverParams) (protocol.HoverResult, error) {
+		calls = append(calls, protocol.MethodTextDocumentHover)
+		return protocol.HoverResult{Null: true}, nil
+	})
+	router.HandleDidOpen(func(ctx context.Context, params protocol.DidOpenTextDocumentParams) error {
+		calls = append(calls, protocol.MethodTextDocumentDidOpen)
+		return nil
+	})
+
+	return protocol.NewLifecycleGuard(router), &calls
+}
+
+func expectErrorCode(t *testing.T, err error, code int64) {
+	t.Helper()
+
+	var rerr *protocol.ResponseError
+	if !errors.As(err, &rerr) || rerr.Code != code {
+		t.Fatalf("expected error code %d, got %v", code, err)
+	}
+}
+
+func Test_LifecycleGuard_RejectsBeforeInitialize(t *testing.T) {
+	guard, calls := newGuardedRouter(t)
+	ctx := context.Background()
+
+	_, err := guard.Handle(ctx, newRequest(1, protocol.MethodTextDocumentHover, `{}`))
+	expectErrorCode(t, err, protocol.RPCServerNotInitialized)
+
+	_, err = guard.Handle(ctx, newRequest(2, protocol.MethodShutdown, ""))
+	expectErrorCode(t, err, protocol.RPCServerNotInitialized)
+
+	if _, err := guard.Handle(ctx, newRequest(0, protocol.MethodTextDocumentDidOpen, `{}`)); err != nil {
+		t.Fatalf("expected notification to be dropped silently, got %v", err)
+	}
+
+	if len(*calls) != 0 {
+		t.Fatalf("expected no handler calls, got %v", *calls)
+	}
+
+	if guard.State() != protocol.LifecycleStateUninitialized {
+		t.Fatalf("unexpected state %s", guard.State())
+	}
+}
+
+func Test_LifecycleGuard_FailedInitializeCanBeRetried(t *testing.T) {
+	guard, _ := newGuardedRouter(t)
+	ctx := context.Background()
+
+	if _, err := guard.Handle(ctx, newRequest(1, protocol.MethodInitialize, `{"rootPath":"fail"}`)); err == nil {
+		t.Fatalf("expected initialize error")
+	}
+
+	if guard.State() != protocol.LifecycleStateUninitialized {
+		t.Fatalf("unexpected state %s", guard.State())
+	}
+
+	if _, err := guard.Handle(ctx, newRequest(2, protocol.MethodInitialize, `{}`)); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+
+	_, err := guard.Handle(ctx, newRequest(3, protocol.MethodInitialize, `{}`))
+	expectErrorCode(t, err, protocol.RPCInvalidRequest)
+}
+
+func Test_LifecycleGuard_FullLifecycle(t *testing.T) {
+	guard, calls := newGuardedRouter(t)
+	ctx := context.Background()
+
+	steps := []*protocol.Request{
+		newRequest(1, protocol.MethodInitialize, `{}`),
+		newRequest(0, protocol.MethodInitialized, `{}`),
+		newRequest(0, protocol.MethodTextDocumentDidOpen, `{}`),
+		newRequest(2, protocol.MethodTextDocumentHover, `{}`),
+	}
+
+	for _, req := range steps {
+		if _, err := guard.Handle(ctx, req); err != nil {
+			t.Fatalf("%s failed: %v", req.Method, err)
+		}
+	}
+
+	if len(*calls) != len(steps) {
+		t.Fatalf("expected %d handler calls, got %v", len(steps), *calls)
+	}
+
+	result, err := guard.Handle(ctx, newRequest(3, protocol.MethodShutdown, ""))
+	if err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if raw, ok := result.(json.RawMessage); !ok || string(raw) != "null" {
+		t.Fatalf("expected null shutdown result, got %v", result)
+	}
+
+	_, err = guard.Handle(ctx, newRequest(4, protocol.MethodTextDocumentHover, `{}`))
+	expectErrorCode(t, err, protocol.RPCInvalidRequest)
+
+	_, err = guard.Handle(ctx, newRequest(5, protocol.MethodShutdown, ""))
+	expectErrorCode(t, err, protocol.RPCInvalidRequest)
+
+	if _, err := guard.Handle(ctx, newRequest(0, protocol.MethodTextDocumentDidOpen, `{}`)); err != nil {
+		t.Fatalf("expected notification to be dropped silently, got %v", err)
+	}
+
+	if len(*calls) != len(steps) {
+		t.Fatalf("expected no handler calls after shutdown, got %v", *calls)
+	}
+
+	if _, err := guard.Handle(ctx, newRequest(0, protocol.MethodExit, "")); err != nil {
+		t.Fatalf("exit failed: %v", err)
+	}
+
+	select {
+	case <-guard.Exited():
+	default:
+		t.Fatalf("expected Exited to be closed")
+	}
+
+	if guard.ExitCode() != 0 {
+		t.Fatalf("expected exit code 0, got %d", guard.ExitCode())
+	}
+}
+
+func Test_LifecycleGuard_ExitWithoutShutdown(t *testing.T) {
+	for _, initialize := range []bool{false, true} {
+		guard, _ := newGuardedRouter(t)
+		ctx := context.Background()
+
+		if initialize {
+			if _, err := guard.Handle(ctx, newRequest(1, protocol.MethodInitialize, `{}`)); err != nil {
+				t.Fatalf("initialize failed: %v", err)
+			}
+		}
+
+		if _, err := guard.Handle(ctx, newRequest(0, protocol.MethodExit, "")); err != nil {
+			t.Fatalf("exit failed: %v", err)
+		}
+
+		if guard.State() != protocol.LifecycleStateExited {
+			t.Fatalf("unexpected state %s", guard.State())
+		}
+
+		if guard.ExitCode() != 1 {
+			t.Fatalf("expected exit code 1, got %d", guard.ExitCode())
+		}
+	}
+}
+
+func Test_LifecycleGuard_ForwardsShutdownAndExit(t *testing.T) {
+	var calls []string
+	router := protocol.NewRouter()
+	router.HandleInitialize(func(ctx context.Context, params protocol.InitializeParams) (protocol.InitializeResult, error) {
+		return protocol.InitializeResult{}, nil
+	})
+	router.HandleShutdown(func(ctx context.Context) error {
+		calls = append(calls, protocol.MethodShutdown)
+		return nil
+	})
+	router.HandleExit(func(ctx context.Context) error {
+		calls = append(calls, protocol.MethodExit)
+		return nil
+	})
+
+	guard := protocol.NewLifecycleGuard(router)
+	ctx := context.Background()
+
+	for _, req := range []*protocol.Request{
+		newRequest(1, protocol.MethodInitialize, `{}`),
+		newRequest(2, protocol.MethodShutdown, ""),
+		newRequest(0, protocol.MethodExit, ""),
+	} {
+		if _, err := guard.Handle(ctx, req); err != nil {
+			t.Fatalf("%s failed: %v", req.Method, err)
+		}
+	}
+
+	if len(calls) != 2 || calls[0] != protocol.MethodShutdown || calls[1] != protocol.MethodExit {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+}
@@ -44,4 +44,11 @@ func Test_Lifecycle_StructsUnmarshalValidJSON(t *testing.T) {
 	if cancel.Id.String() != "42" {
 		t.Fatalf("unexpected CancelParams id: %s", cancel.Id.String())
 	}
+
+	if err := json.Unmarshal([]byte(`{"id":"req-7"}`), &cancel); err != nil {
+		t.Fatalf("unmarshal CancelParams with string id failed: %v", err)
+	}
+	if !cancel.Id.IsString || cancel.Id.String() != "req-7" {
+		t.Fatalf("unexpected CancelParams id: %+v", cancel.Id)
+	}
 }
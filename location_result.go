@@ -0,0 +1,78 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// LocationResult is the result of the goto requests: textDocument/definition,
+// textDocument/declaration, textDocument/typeDefinition and
+// textDocument/implementation.
+//
+// It can be a single Location, a slice of Locations, a slice of LocationLinks or null.
+// LocationLinks may only be returned if the client announced `linkSupport`
+// for the request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_definition
+type LocationResult struct {
+	Location      *Location
+	LocationList  []Location
+	LocationLinks []LocationLink
+	Null          bool
+}
+
+func (r LocationResult) MarshalJSON() ([]byte, error) {
+	if r.Location != nil {
+		return json.Marshal(r.Location)
+	}
+	if r.LocationList != nil {
+		return json.Marshal(r.LocationList)
+	}
+	if r.LocationLinks != nil {
+		return json.Marshal(r.LocationLinks)
+	}
+	return []byte("null"), nil
+}
+
+func (r *LocationResult) UnmarshalJSON(data []byte) error {
+	// Make sure object is reset.
+	*r = LocationResult{}
+
+	// Check for null
+	if string(data) == "null" {
+		r.Null = true
+		return nil
+	}
+
+	// Try single Location
+	var loc Location
+	if err := json.Unmarshal(data, &loc); err == nil && loc.URI != "" {
+		r.Location = &loc
+		return nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return errors.New("invalid location result: not null, Location, []Location, or []LocationLink")
+	}
+
+	// An array is a []LocationLink if its elements carry a target URI.
+	var probe struct {
+		TargetURI DocumentURI `json:"targetUri"`
+	}
+	if len(items) > 0 && json.Unmarshal(items[0], &probe) == nil && probe.TargetURI != "" {
+		var linkList []LocationLink
+		if err := json.Unmarshal(data, &linkList); err != nil {
+			return err
+		}
+		r.LocationLinks = linkList
+		return nil
+	}
+
+	var locList []Location
+	if err := json.Unmarshal(data, &locList); err != nil {
+		return err
+	}
+	r.LocationList = locList
+	return nil
+}
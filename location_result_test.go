@@ -0,0 +1,111 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_LocationResult_UnmarshalVariants(t *testing.T) {
+	location := `{"uri":"file:///a.php","range":{"start":{"line":1,"character":0},"end":{"line":1,"character":4}}}`
+	link := `{"targetUri":"file:///b.php","targetRange":{"start":{"line":2,"character":0},"end":{"line":9,"character":1}},"targetSelectionRange":{"start":{"line":2,"character":6},"end":{"line":2,"character":10}}}`
+
+	tests := []struct {
+		name  string
+		json  string
+		check func(t *testing.T, r protocol.LocationResult)
+	}{
+		{
+			name: "null",
+			json: `null`,
+			check: func(t *testing.T, r protocol.LocationResult) {
+				if !r.Null {
+					t.Fatalf("expected null result, got %+v", r)
+				}
+			},
+		},
+		{
+			name: "single",
+			json: location,
+			check: func(t *testing.T, r protocol.LocationResult) {
+				if r.Location == nil || r.Location.URI != "file:///a.php" {
+					t.Fatalf("expected single location, got %+v", r)
+				}
+			},
+		},
+		{
+			name: "array",
+			json: `[` + location + `]`,
+			check: func(t *testing.T, r protocol.LocationResult) {
+				if len(r.LocationList) != 1 || r.LocationLinks != nil {
+					t.Fatalf("expected location list, got %+v", r)
+				}
+			},
+		},
+		{
+			name: "empty array",
+			json: `[]`,
+			check: func(t *testing.T, r protocol.LocationResult) {
+				if r.LocationList == nil || len(r.LocationList) != 0 {
+					t.Fatalf("expected empty location list, got %+v", r)
+				}
+			},
+		},
+		{
+			name: "links",
+			json: `[` + link + `]`,
+			check: func(t *testing.T, r protocol.LocationResult) {
+				if len(r.LocationLinks) != 1 || r.LocationList != nil {
+					t.Fatalf("expected location links, got %+v", r)
+				}
+				if r.LocationLinks[0].TargetURI != "file:///b.php" || r.LocationLinks[0].TargetSelectionRange.Start.Character != 6 {
+					t.Fatalf("unexpected location link: %+v", r.LocationLinks[0])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result protocol.LocationResult
+			if err := json.Unmarshal([]byte(tt.json), &result); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			tt.check(t, result)
+
+			data, err := json.Marshal(result)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			if string(data) != tt.json {
+				t.Fatalf("expected round trip %s, got %s", tt.json, data)
+			}
+		})
+	}
+}
+
+func Test_LocationResult_UnmarshalInvalid(t *testing.T) {
+	var result protocol.LocationResult
+	if err := json.Unmarshal([]byte(`"file:///a.php"`), &result); err == nil {
+		t.Fatalf("expected error for string result")
+	}
+}
+
+func Test_LocationResult_SharedByGotoRequests(t *testing.T) {
+	data := []byte(`[{"targetUri":"file:///app/Models/User.php","targetRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"targetSelectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}]`)
+
+	var definition protocol.DefinitionResponse
+	var declaration protocol.DeclarationResponse
+	var typeDefinition protocol.TypeDefinitionResponse
+	var implementation protocol.ImplementationResponse
+
+	for _, target := range []*protocol.LocationResult{&definition, &declaration, &typeDefinition, &implementation} {
+		if err := json.Unmarshal(data, target); err != nil {
+			t.Fatalf("unmarshal failed: %v", err)
+		}
+		if len(target.LocationLinks) != 1 {
+			t.Fatalf("expected location links, got %+v", target)
+		}
+	}
+}
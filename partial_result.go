@@ -0,0 +1,182 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// ErrPartialResultFinished is returned when sending partial results after
+// the final result was produced.
+var ErrPartialResultFinished = errors.New("partial result already finished")
+
+// PartialResultSender streams the items of a request result through
+// `$/progress` notifications for a partial result token.
+//
+// Items are batched and sent once batchSize items are pending. If the
+// request carried no partial result token nothing is streamed and Result
+// returns every item. Once a partial result was sent, Result returns an
+// empty slice, as the specification requires the final response to be empty
+// in that case.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#partialResults
+type PartialResultSender[T any] struct {
+	token     *ProgressToken
+	notify    NotifyFunc
+	batchSize int
+
+	mu       sync.Mutex
+	pending  []T
+	sent     bool
+	finished bool
+}
+
+// NewPartialResultSender creates a sender for the partial result token of a
+// request. A batchSize of zero or less sends every call to Send right away.
+func NewPartialResultSender[T any](token *ProgressToken, notify NotifyFunc, batchSize int) *PartialResultSender[T] {
+	return &PartialResultSender[T]{
+		token:     token,
+		notify:    notify,
+		batchSize: batchSize,
+	}
+}
+
+// Streaming reports whether results are streamed, that is whether the
+// request carried a partial result token.
+func (s *PartialResultSender[T]) Streaming() bool {
+	return s.token != nil
+}
+
+// Send adds items to the result, sending a batch if enough items are
+// pending.
+func (s *PartialResultSender[T]) Send(ctx context.Context, items ...T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finished {
+		return ErrPartialResultFinished
+	}
+
+	s.pending = append(s.pending, items...)
+	if s.token == nil || len(s.pending) < s.batchSize {
+		return nil
+	}
+	return s.flush(ctx)
+}
+
+// Flush sends all pending items as a partial result.
+func (s *PartialResultSender[T]) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finished {
+		return ErrPartialResultFinished
+	}
+	if s.token == nil {
+		return nil
+	}
+	return s.flush(ctx)
+}
+
+// Result finishes the sender and returns the value for the final response.
+//
+// If no partial result was sent yet, the pending items are returned as the
+// full result. Otherwise the pending items are sent as a last partial result
+// and an empty, non-nil slice is returned.
+func (s *PartialResultSender[T]) Result(ctx context.Context) ([]T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finished {
+		return nil, ErrPartialResultFinished
+	}
+	s.finished = true
+
+	if !s.sent {
+		result := s.pending
+		s.pending = nil
+		if result == nil {
+			result = []T{}
+		}
+		return result, nil
+	}
+
+	if err := s.flush(ctx); err != nil {
+		return nil, err
+	}
+	return []T{}, nil
+}
+
+func (s *PartialResultSender[T]) flush(ctx context.Context) error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := s.notify(ctx, MethodProgress, ProgressParams{Token: *s.token, Value: s.pending}); err != nil {
+		return err
+	}
+	s.pending = nil
+	s.sent = true
+	return nil
+}
+
+// PartialResultCollector reassembles the partial results a client receives
+// for a partial result token.
+//
+// Pass every `$/progress` notification to Handle and the final response to
+// Result.
+type PartialResultCollector[T any] struct {
+	token ProgressToken
+
+	mu    sync.Mutex
+	items []T
+}
+
+// NewPartialResultCollector creates a collector for token, which should be
+// sent as the partialResultToken of the request.
+func NewPartialResultCollector[T any](token ProgressToken) *PartialResultCollector[T] {
+	return &PartialResultCollector[T]{token: token}
+}
+
+// Token returns the partial result token of the collector.
+func (c *PartialResultCollector[T]) Token() ProgressToken {
+	return c.token
+}
+
+// Handle collects the partial result carried by params. It reports false if
+// the notification is for another token.
+func (c *PartialResultCollector[T]) Handle(params ProgressParams) (bool, error) {
+	if params.Token != c.token {
+		return false, nil
+	}
+
+	data, err := json.Marshal(params.Value)
+	if err != nil {
+		return true, err
+	}
+
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return true, err
+	}
+
+	c.mu.Lock()
+	c.items = append(c.items, items...)
+	c.mu.Unlock()
+	return true, nil
+}
+
+// Result returns the collected partial results followed by the items of the
+// final response.
+func (c *PartialResultCollector[T]) Result(final []T) []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([]T, 0, len(c.items)+len(final))
+	result = append(result, c.items...)
+	return append(result, final...)
+}
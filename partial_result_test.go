@@ -0,0 +1,151 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_PartialResult_SenderBatches(t *testing.T) {
+	notify, sent := newRecordingNotify(t)
+	token := protocol.StringProgressToken("defs")
+	sender := protocol.NewPartialResultSender[int](&token, notify, 2)
+	ctx := context.Background()
+
+	if !sender.Streaming() {
+		t.Fatalf("expected sender to stream with a token")
+	}
+
+	if err := sender.Send(ctx, 1); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("expected no notification before batch is full, got %d", len(*sent))
+	}
+
+	if err := sender.Send(ctx, 2, 3); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if err := sender.Send(ctx, 4); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	result, err := sender.Result(ctx)
+	if err != nil {
+		t.Fatalf("result failed: %v", err)
+	}
+	if result == nil || len(result) != 0 {
+		t.Fatalf("expected empty final result, got %#v", result)
+	}
+
+	expected := []string{
+		`{"token":"defs","value":[1,2,3]}`,
+		`{"token":"defs","value":[4]}`,
+	}
+	if len(*sent) != len(expected) {
+		t.Fatalf("expected %d notifications, got %d", len(expected), len(*sent))
+	}
+	for i, n := range *sent {
+		if n.method != protocol.MethodProgress || n.params != expected[i] {
+			t.Fatalf("notification %d: expected %s, got %s %s", i, expected[i], n.method, n.params)
+		}
+	}
+
+	if err := sender.Send(ctx, 5); !errors.Is(err, protocol.ErrPartialResultFinished) {
+		t.Fatalf("expected ErrPartialResultFinished, got %v", err)
+	}
+}
+
+func Test_PartialResult_SenderWithoutPartials(t *testing.T) {
+	notify, sent := newRecordingNotify(t)
+	token := protocol.NumberProgressToken(3)
+	ctx := context.Background()
+
+	for _, sender := range []*protocol.PartialResultSender[int]{
+		protocol.NewPartialResultSender[int](nil, notify, 1),
+		protocol.NewPartialResultSender[int](&token, notify, 10),
+	} {
+		if err := sender.Send(ctx, 1, 2); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+
+		result, err := sender.Result(ctx)
+		if err != nil {
+			t.Fatalf("result failed: %v", err)
+		}
+		if len(result) != 2 {
+			t.Fatalf("expected full result, got %#v", result)
+		}
+	}
+
+	if len(*sent) != 0 {
+		t.Fatalf("expected no notifications, got %d", len(*sent))
+	}
+}
+
+func Test_PartialResult_CollectorReassembles(t *testing.T) {
+	token := protocol.StringProgressToken("defs")
+	collector := protocol.NewPartialResultCollector[protocol.Location](token)
+
+	notifications := []string{
+		`{"token":"defs","value":[{"uri":"file:///a.php","range":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}}}]}`,
+		`{"token":"other","value":[{"uri":"file:///x.php","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}]}`,
+		`{"token":"defs","value":[{"uri":"file:///b.php","range":{"start":{"line":2,"character":0},"end":{"line":2,"character":3}}}]}`,
+	}
+
+	handled := 0
+	for _, n := range notifications {
+		var params protocol.ProgressParams
+		if err := json.Unmarshal([]byte(n), &params); err != nil {
+			t.Fatalf("unmarshal failed: %v", err)
+		}
+
+		ok, err := collector.Handle(params)
+		if err != nil {
+			t.Fatalf("handle failed: %v", err)
+		}
+		if ok {
+			handled++
+		}
+	}
+
+	if handled != 2 {
+		t.Fatalf("expected 2 handled notifications, got %d", handled)
+	}
+
+	result := collector.Result(nil)
+	if len(result) != 2 || result[0].URI != "file:///a.php" || result[1].URI != "file:///b.php" {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+}
+
+func Test_PartialResult_SenderToCollector(t *testing.T) {
+	token := protocol.NumberProgressToken(9)
+	collector := protocol.NewPartialResultCollector[string](token)
+	ctx := context.Background()
+
+	notify := func(ctx context.Context, method string, params any) error {
+		_, err := collector.Handle(params.(protocol.ProgressParams))
+		return err
+	}
+
+	sender := protocol.NewPartialResultSender[string](&token, notify, 1)
+	for _, item := range []string{"a", "b", "c"} {
+		if err := sender.Send(ctx, item); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+	}
+
+	final, err := sender.Result(ctx)
+	if err != nil {
+		t.Fatalf("result failed: %v", err)
+	}
+
+	result := collector.Result(final)
+	if len(result) != 3 || result[0] != "a" || result[2] != "c" {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+}
@@ -0,0 +1,177 @@
+package protocol
+
+import "unicode/utf8"
+
+// ByteOffset returns the byte offset into line of a character offset
+// counted in code units of the encoding.
+//
+// Character offsets past the end of line resolve to len(line), and offsets
+// pointing inside a character resolve to the start of that character.
+// Unknown encodings are treated as UTF-16, the protocol default.
+func (k PositionEncodingKind) ByteOffset(line string, character uint32) int {
+	if k == PositionEncodingKindUTF8 {
+		offset := len(line)
+		if uint64(character) < uint64(offset) {
+			offset = int(character)
+		}
+		for offset > 0 && offset < len(line) && !utf8.RuneStart(line[offset]) {
+			offset--
+		}
+		return offset
+	}
+
+	var units uint32
+	for i, r := range line {
+		n := k.runeLength(r)
+		if units+n > character {
+			return i
+		}
+		units += n
+	}
+	return len(line)
+}
+
+// Length returns the length of s counted in code units of the encoding.
+// Unknown encodings are treated as UTF-16, the protocol default.
+func (k PositionEncodingKind) Length(s string) uint32 {
+	if k == PositionEncodingKindUTF8 {
+		return uint32(len(s))
+	}
+
+	var units uint32
+	for _, r := range s {
+		units += k.runeLength(r)
+	}
+	return units
+}
+
+func (k PositionEncodingKind) runeLength(r rune) uint32 {
+	if k != PositionEncodingKindUTF32 && r >= 0x10000 && r <= utf8.MaxRune {
+		return 2
+	}
+	return 1
+}
+
+// PositionToOffset converts a position in text, with the character offset
+// counted in the given encoding, to a byte offset into text.
+//
+// Positions past the end of a line resolve to the end of that line and
+// positions past the last line resolve to the end of text.
+func PositionToOffset(text string, pos Position, encoding PositionEncodingKind) int {
+	start, end, ok := lineBoundsIn(text, pos.Line)
+	if !ok {
+		return len(text)
+	}
+	return start + encoding.ByteOffset(text[start:end], pos.Character)
+}
+
+// OffsetToPosition converts a byte offset into text to a position with
+// the character offset counted in the given encoding.
+//
+// Offsets are clamped to text, and offsets inside a multi-byte character
+// or a line terminator resolve to its start.
+func OffsetToPosition(text string, offset int, encoding PositionEncodingKind) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(text) {
+		offset = len(text)
+	}
+
+	var line uint32
+	start := 0
+	for i := 0; i < offset; i++ {
+		switch text[i] {
+		case '\r':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				if i+1 == offset {
+					// The offset points between \r and \n.
+					continue
+				}
+				i++
+			}
+			line++
+			start = i + 1
+		case '\n':
+			line++
+			start = i + 1
+		}
+	}
+
+	_, end, _ := lineBoundsIn(text[start:], 0)
+	return positionIn(text, line, start, start+end, offset, encoding)
+}
+
+// ConvertPosition converts a position in text from one encoding to another.
+func ConvertPosition(text string, pos Position, from, to PositionEncodingKind) Position {
+	if from == to {
+		return pos
+	}
+
+	start, end, ok := lineBoundsIn(text, pos.Line)
+	if !ok {
+		return OffsetToPosition(text, len(text), to)
+	}
+
+	offset := start + from.ByteOffset(text[start:end], pos.Character)
+	return positionIn(text, pos.Line, start, end, offset, to)
+}
+
+// ConvertRange converts a range in text from one encoding to another.
+func ConvertRange(text string, r Range, from, to PositionEncodingKind) Range {
+	return Range{
+		Start: ConvertPosition(text, r.Start, from, to),
+		End:   ConvertPosition(text, r.End, from, to),
+	}
+}
+
+// ConvertTextEdit converts the range of a text edit on text from one encoding to another.
+func ConvertTextEdit(text string, edit TextEdit, from, to PositionEncodingKind) TextEdit {
+	return TextEdit{
+		Range:   ConvertRange(text, edit.Range, from, to),
+		NewText: edit.NewText,
+	}
+}
+
+// positionIn returns the position of offset, which must be within the
+// given line, whose content spans text[start:end].
+func positionIn(text string, line uint32, start, end, offset int, encoding PositionEncodingKind) Position {
+	if offset > end {
+		offset = end
+	}
+	for offset > start && offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+
+	return Position{
+		Line:      line,
+		Character: encoding.Length(text[start:offset]),
+	}
+}
+
+// lineBoundsIn returns the byte offsets of the start and end of a line in
+// text, excluding the line terminator. ok is false if text has fewer lines.
+func lineBoundsIn(text string, line uint32) (start, end int, ok bool) {
+	var current uint32
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != '\n' && c != '\r' {
+			continue
+		}
+
+		if current == line {
+			return start, i, true
+		}
+
+		if c == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+			i++
+		}
+		current++
+		start = i + 1
+	}
+
+	if current == line {
+		return start, len(text), true
+	}
+	return 0, 0, false
+}
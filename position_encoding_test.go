@@ -0,0 +1,137 @@
+package protocol_test
+
+import (
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+// "é" is 2 bytes, 1 UTF-16 unit and 1 code point.
+// "😀" is 4 bytes, 2 UTF-16 units and 1 code point.
+const encodingText = "<?php\n$a = 'é😀';\r\necho $a;"
+
+func Test_PositionEncoding_Length(t *testing.T) {
+	tests := map[protocol.PositionEncodingKind]uint32{
+		protocol.PositionEncodingKindUTF8:  7,
+		protocol.PositionEncodingKindUTF16: 4,
+		protocol.PositionEncodingKindUTF32: 3,
+		"":                                 4,
+	}
+
+	for encoding, want := range tests {
+		if got := encoding.Length("aé😀"); got != want {
+			t.Fatalf("%q: expected length %d, got %d", encoding, want, got)
+		}
+	}
+}
+
+func Test_PositionEncoding_ByteOffset(t *testing.T) {
+	tests := []struct {
+		encoding  protocol.PositionEncodingKind
+		character uint32
+		want      int
+	}{
+		{protocol.PositionEncodingKindUTF8, 3, 3},
+		{protocol.PositionEncodingKindUTF8, 2, 1},
+		{protocol.PositionEncodingKindUTF8, 100, 7},
+		{protocol.PositionEncodingKindUTF16, 2, 3},
+		{protocol.PositionEncodingKindUTF16, 3, 3},
+		{protocol.PositionEncodingKindUTF16, 4, 7},
+		{protocol.PositionEncodingKindUTF32, 2, 3},
+		{protocol.PositionEncodingKindUTF32, 3, 7},
+	}
+
+	for _, test := range tests {
+		if got := test.encoding.ByteOffset("aé😀", test.character); got != test.want {
+			t.Fatalf("%s character %d: expected offset %d, got %d", test.encoding, test.character, test.want, got)
+		}
+	}
+}
+
+func Test_PositionEncoding_PositionToOffsetAndBack(t *testing.T) {
+	// Position right after the emoji on the second line.
+	tests := []struct {
+		encoding protocol.PositionEncodingKind
+		pos      protocol.Position
+	}{
+		{protocol.PositionEncodingKindUTF8, protocol.Position{Line: 1, Character: 12}},
+		{protocol.PositionEncodingKindUTF16, protocol.Position{Line: 1, Character: 9}},
+		{protocol.PositionEncodingKindUTF32, protocol.Position{Line: 1, Character: 8}},
+	}
+
+	for _, test := range tests {
+		offset := protocol.PositionToOffset(encodingText, test.pos, test.encoding)
+		if offset != 18 {
+			t.Fatalf("%s: expected offset 18, got %d", test.encoding, offset)
+		}
+
+		if pos := protocol.OffsetToPosition(encodingText, offset, test.encoding); pos != test.pos {
+			t.Fatalf("%s: expected %+v, got %+v", test.encoding, test.pos, pos)
+		}
+	}
+
+	if pos := protocol.OffsetToPosition(encodingText, 21, protocol.PositionEncodingKindUTF16); pos != (protocol.Position{Line: 1, Character: 11}) {
+		t.Fatalf("expected offset between CR and LF to resolve to end of line, got %+v", pos)
+	}
+
+	if pos := protocol.OffsetToPosition(encodingText, 22, protocol.PositionEncodingKindUTF16); pos != (protocol.Position{Line: 2}) {
+		t.Fatalf("expected start of third line, got %+v", pos)
+	}
+
+	if offset := protocol.PositionToOffset(encodingText, protocol.Position{Line: 7}, protocol.PositionEncodingKindUTF16); offset != len(encodingText) {
+		t.Fatalf("expected position past last line to clamp to end, got %d", offset)
+	}
+}
+
+func Test_PositionEncoding_ConvertRangeAndTextEdit(t *testing.T) {
+	// Range covering 'é😀' on the second line.
+	utf16 := protocol.Range{
+		Start: protocol.Position{Line: 1, Character: 6},
+		End:   protocol.Position{Line: 1, Character: 9},
+	}
+
+	utf8 := protocol.ConvertRange(encodingText, utf16, protocol.PositionEncodingKindUTF16, protocol.PositionEncodingKindUTF8)
+	if utf8.Start.Character != 6 || utf8.End.Character != 12 {
+		t.Fatalf("unexpected UTF-8 range: %+v", utf8)
+	}
+
+	utf32 := protocol.ConvertRange(encodingText, utf8, protocol.PositionEncodingKindUTF8, protocol.PositionEncodingKindUTF32)
+	if utf32.Start.Character != 6 || utf32.End.Character != 8 {
+		t.Fatalf("unexpected UTF-32 range: %+v", utf32)
+	}
+
+	edit := protocol.ConvertTextEdit(encodingText, protocol.TextEdit{Range: utf32, NewText: "x"}, protocol.PositionEncodingKindUTF32, protocol.PositionEncodingKindUTF16)
+	if edit.Range != utf16 || edit.NewText != "x" {
+		t.Fatalf("unexpected converted edit: %+v", edit)
+	}
+}
+
+func Test_PositionEncoding_DocumentStoreUsesNegotiatedEncoding(t *testing.T) {
+	store := protocol.NewDocumentStore()
+	store.SetPositionEncoding(protocol.PositionEncodingKindUTF8)
+
+	doc := store.DidOpen(protocol.DidOpenTextDocumentParams{
+		TextDocument: protocol.TextDocumentItem{URI: "file:///a.php", Version: 1, Text: "'é'"},
+	})
+
+	doc, err := store.DidChange(protocol.DidChangeTextDocumentParams{
+		TextDocument: protocol.VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: "file:///a.php"},
+			Version:                2,
+		},
+		ContentChanges: []protocol.TextDocumentContentChangeEvent{{
+			Range: &protocol.Range{
+				Start: protocol.Position{Character: 1},
+				End:   protocol.Position{Character: 3},
+			},
+			Text: "e",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("change failed: %v", err)
+	}
+
+	if doc.Text != "'e'" || doc.Encoding != protocol.PositionEncodingKindUTF8 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package protocol
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// stillActive is the exit code reported for a running process.
+	stillActive = 259
+
+	// processQueryLimitedInformation is the PROCESS_QUERY_LIMITED_INFORMATION
+	// access right, which is granted for more processes than
+	// PROCESS_QUERY_INFORMATION.
+	processQueryLimitedInformation = 0x1000
+)
+
+// processAlive reports whether a process with the given pid exists. A
+// process we may not query exists, matching EPERM on other platforms.
+func processAlive(pid int) bool {
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return errors.Is(err, syscall.ERROR_ACCESS_DENIED)
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return errors.Is(err, syscall.ERROR_ACCESS_DENIED)
+	}
+	return code == stillActive
+}
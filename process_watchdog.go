@@ -0,0 +1,49 @@
+package protocol
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultProcessWatchInterval is the polling interval used by
+// WatchParentProcess when no interval is given.
+const DefaultProcessWatchInterval = 3 * time.Second
+
+// WatchProcess polls the process pid every interval and calls onExit once
+// the process is gone. Watching stops when ctx is done, without calling
+// onExit.
+func WatchProcess(ctx context.Context, pid int, interval time.Duration, onExit func()) {
+	if interval <= 0 {
+		interval = DefaultProcessWatchInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !processAlive(pid) {
+					onExit()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// WatchParentProcess watches the process that started the server, as given
+// by the `processId` of the `initialize` request, and calls onExit once it is
+// gone so an orphaned server can stop. It does nothing if the client did not
+// send a process id.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#initializeParams
+func WatchParentProcess(ctx context.Context, params InitializeParams, interval time.Duration, onExit func()) {
+	if params.ProcessID == nil || *params.ProcessID <= 0 {
+		return
+	}
+	WatchProcess(ctx, *params.ProcessID, interval, onExit)
+}
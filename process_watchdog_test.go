@@ -0,0 +1,99 @@
+package protocol_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_ProcessWatchdog_DetectsExit(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("requires linux")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start child process: %v", err)
+	}
+	pid := cmd.Process.Pid
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	exited := make(chan struct{})
+	protocol.WatchParentProcess(ctx, protocol.InitializeParams{ProcessID: &pid}, 10*time.Millisecond, func() {
+		close(exited)
+	})
+
+	select {
+	case <-exited:
+		t.Fatalf("watchdog fired while the process was alive")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := cmd.Process.Kill(); err != nil {
+		t.Fatalf("kill failed: %v", err)
+	}
+	_ = cmd.Wait()
+
+	select {
+	case <-exited:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("watchdog did not detect the process exit")
+	}
+}
+
+func Test_ProcessWatchdog_StopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	called := make(chan struct{}, 1)
+
+	// A pid that cannot exist; cancelling before the first tick must win.
+	cancel()
+	protocol.WatchProcess(ctx, 1<<30, 10*time.Millisecond, func() {
+		called <- struct{}{}
+	})
+
+	select {
+	case <-called:
+		t.Fatalf("expected no exit callback after the context was cancelled")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func Test_ProcessWatchdog_IgnoresMissingProcessID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	called := make(chan struct{}, 1)
+	protocol.WatchParentProcess(ctx, protocol.InitializeParams{}, time.Millisecond, func() {
+		called <- struct{}{}
+	})
+
+	select {
+	case <-called:
+		t.Fatalf("unexpected exit callback")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func Test_ProcessWatchdog_RunningParentProcess(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	called := make(chan struct{}, 1)
+	pid := os.Getpid()
+	protocol.WatchParentProcess(ctx, protocol.InitializeParams{ProcessID: &pid}, time.Millisecond, func() {
+		called <- struct{}{}
+	})
+
+	select {
+	case <-called:
+		t.Fatalf("unexpected exit callback for a running process")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
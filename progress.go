@@ -1,8 +1,13 @@
 package protocol
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
 
 const (
+	MethodProgress                     = "$/progress"
 	MethodWindowWorkDoneProgressCreate = "window/workDoneProgress/create"
 	MethodWindowWorkDoneProgressCancel = "window/workDoneProgress/cancel"
 )
@@ -16,7 +21,24 @@ type ProgressToken struct {
 	number int32
 }
 
-func (v *ProgressToken) MarshalJSON() ([]byte, error) {
+// NumberProgressToken creates a numeric progress token.
+func NumberProgressToken(n int32) ProgressToken {
+	return ProgressToken{number: n}
+}
+
+// StringProgressToken creates a string progress token.
+func StringProgressToken(s string) ProgressToken {
+	return ProgressToken{name: s}
+}
+
+func (v ProgressToken) String() string {
+	if v.name != "" {
+		return v.name
+	}
+	return strconv.FormatInt(int64(v.number), 10)
+}
+
+func (v ProgressToken) MarshalJSON() ([]byte, error) {
 	if v.name != "" {
 		return json.Marshal(v.name)
 	}
@@ -64,3 +86,180 @@ type WorkDoneProgressCancelParams struct {
 	// The token to be used to report progress.
 	Token ProgressToken `json:"token"`
 }
+
+// ProgressParams - The parameters of a `$/progress` notification.
+//
+// Value holds the raw JSON value after unmarshalling; use WorkDoneProgress to
+// decode it as work done progress.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#progress
+type ProgressParams struct {
+	// The progress token provided by the client or server.
+	Token ProgressToken `json:"token"`
+
+	// The progress data.
+	Value LSPAny `json:"value"`
+}
+
+func (p *ProgressParams) UnmarshalJSON(data []byte) error {
+	var temp struct {
+		Token ProgressToken   `json:"token"`
+		Value json.RawMessage `json:"value"`
+	}
+
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	*p = ProgressParams{Token: temp.Token}
+	if temp.Value != nil {
+		p.Value = temp.Value
+	}
+	return nil
+}
+
+// WorkDoneProgress decodes Value as a WorkDoneProgressBegin,
+// WorkDoneProgressReport or WorkDoneProgressEnd.
+func (p ProgressParams) WorkDoneProgress() (WorkDoneProgressValue, error) {
+	data, err := json.Marshal(p.Value)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalWorkDoneProgressValue(data)
+}
+
+// WorkDoneProgressKind - The kind of a work done progress value.
+type WorkDoneProgressKind string
+
+const (
+	WorkDoneProgressKindBegin  WorkDoneProgressKind = "begin"
+	WorkDoneProgressKindReport WorkDoneProgressKind = "report"
+	WorkDoneProgressKindEnd    WorkDoneProgressKind = "end"
+)
+
+// WorkDoneProgressValue - represents any work done progress value.
+//
+// Implemented by WorkDoneProgressBegin, WorkDoneProgressReport and
+// WorkDoneProgressEnd.
+type WorkDoneProgressValue interface {
+	isWorkDoneProgressValue()
+}
+
+// unmarshalWorkDoneProgressValue decodes a work done progress value, using
+// the `kind` property to pick the concrete type.
+func unmarshalWorkDoneProgressValue(data []byte) (WorkDoneProgressValue, error) {
+	var temp struct {
+		Kind string `json:"kind"`
+	}
+
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return nil, err
+	}
+
+	switch WorkDoneProgressKind(temp.Kind) {
+	case WorkDoneProgressKindBegin:
+		var begin WorkDoneProgressBegin
+		if err := json.Unmarshal(data, &begin); err != nil {
+			return nil, err
+		}
+		return begin, nil
+	case WorkDoneProgressKindReport:
+		var report WorkDoneProgressReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+		return report, nil
+	case WorkDoneProgressKindEnd:
+		var end WorkDoneProgressEnd
+		if err := json.Unmarshal(data, &end); err != nil {
+			return nil, err
+		}
+		return end, nil
+	}
+
+	return nil, fmt.Errorf("unknown work done progress kind: %s", temp.Kind)
+}
+
+// WorkDoneProgressBegin - Starts a work done progress.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workDoneProgressBegin
+//
+// @since 3.15.0
+type WorkDoneProgressBegin struct {
+	Kind WorkDoneProgressKind `json:"kind"`
+
+	// Mandatory title of the progress operation. Used to briefly inform about
+	// the kind of operation being performed.
+	Title string `json:"title"`
+
+	// Controls if a cancel button should show to allow the user to cancel the
+	// long running operation. Clients that don't support cancellation are
+	// allowed to ignore the setting.
+	Cancellable bool `json:"cancellable,omitempty"`
+
+	// Optional, more detailed associated progress message.
+	Message string `json:"message,omitempty"`
+
+	// Optional progress percentage to display (value 100 is considered 100%).
+	// If not provided infinite progress is assumed.
+	Percentage *uint32 `json:"percentage,omitempty"`
+}
+
+func (WorkDoneProgressBegin) isWorkDoneProgressValue() {}
+
+func (b WorkDoneProgressBegin) MarshalJSON() ([]byte, error) {
+	type workDoneProgressBegin WorkDoneProgressBegin
+	b.Kind = WorkDoneProgressKindBegin
+	return json.Marshal(workDoneProgressBegin(b))
+}
+
+// WorkDoneProgressReport - Reports progress of a work done progress.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workDoneProgressReport
+//
+// @since 3.15.0
+type WorkDoneProgressReport struct {
+	Kind WorkDoneProgressKind `json:"kind"`
+
+	// Controls enablement state of a cancel button. Clients that don't support
+	// cancellation or don't support controlling the button's enablement state
+	// are allowed to ignore the property.
+	Cancellable bool `json:"cancellable,omitempty"`
+
+	// Optional, more detailed associated progress message. If unset, the
+	// previous progress message (if any) is still valid.
+	Message string `json:"message,omitempty"`
+
+	// Optional progress percentage to display (value 100 is considered 100%).
+	// The value should be steadily rising.
+	Percentage *uint32 `json:"percentage,omitempty"`
+}
+
+func (WorkDoneProgressReport) isWorkDoneProgressValue() {}
+
+func (r WorkDoneProgressReport) MarshalJSON() ([]byte, error) {
+	type workDoneProgressReport WorkDoneProgressReport
+	r.Kind = WorkDoneProgressKindReport
+	return json.Marshal(workDoneProgressReport(r))
+}
+
+// WorkDoneProgressEnd - Signals the end of a work done progress.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workDoneProgressEnd
+//
+// @since 3.15.0
+type WorkDoneProgressEnd struct {
+	Kind WorkDoneProgressKind `json:"kind"`
+
+	// Optional, a final message indicating to for example indicate the outcome
+	// of the operation.
+	Message string `json:"message,omitempty"`
+}
+
+func (WorkDoneProgressEnd) isWorkDoneProgressValue() {}
+
+func (e WorkDoneProgressEnd) MarshalJSON() ([]byte, error) {
+	type workDoneProgressEnd WorkDoneProgressEnd
+	e.Kind = WorkDoneProgressKindEnd
+	return json.Marshal(workDoneProgressEnd(e))
+}
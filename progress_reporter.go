@@ -0,0 +1,178 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrProgressCancelled is returned when reporting progress that was
+	// cancelled by the client.
+	ErrProgressCancelled = errors.New("work done progress cancelled")
+
+	// ErrProgressNotStarted is returned when reporting or ending progress
+	// before Begin was called.
+	ErrProgressNotStarted = errors.New("work done progress not started")
+
+	// ErrProgressEnded is returned when reporting progress after End was
+	// called.
+	ErrProgressEnded = errors.New("work done progress already ended")
+)
+
+type progressState int
+
+const (
+	progressIdle progressState = iota
+	progressBegun
+	progressEnded
+)
+
+// ProgressReporter sends work done progress for a single token through
+// `$/progress` notifications.
+//
+// Percentages are clamped to 0..100 and never decrease, as the
+// specification requires them to be steadily rising. Once Cancel is called,
+// usually from a `window/workDoneProgress/cancel` handler, Report returns
+// ErrProgressCancelled so long running work can stop; End may still be
+// called to close the progress in the client.
+type ProgressReporter struct {
+	token  ProgressToken
+	notify NotifyFunc
+
+	mu            sync.Mutex
+	state         progressState
+	hasPercentage bool
+	percentage    uint32
+
+	cancelOnce sync.Once
+	cancelled  chan struct{}
+}
+
+// NewProgressReporter creates a reporter for token that sends notifications
+// with notify.
+func NewProgressReporter(token ProgressToken, notify NotifyFunc) *ProgressReporter {
+	return &ProgressReporter{
+		token:     token,
+		notify:    notify,
+		cancelled: make(chan struct{}),
+	}
+}
+
+// Token returns the progress token of the reporter.
+func (p *ProgressReporter) Token() ProgressToken {
+	return p.token
+}
+
+// Begin starts the progress. Progress is only reported with percentages if
+// begin has one.
+func (p *ProgressReporter) Begin(ctx context.Context, begin WorkDoneProgressBegin) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.state {
+	case progressBegun:
+		return errors.New("work done progress already started")
+	case progressEnded:
+		return ErrProgressEnded
+	}
+
+	if begin.Percentage != nil {
+		p.hasPercentage = true
+		p.percentage = p.clamp(int(*begin.Percentage))
+		begin.Percentage = uint32Ptr(p.percentage)
+	}
+
+	if err := p.send(ctx, begin); err != nil {
+		return err
+	}
+	p.state = progressBegun
+	return nil
+}
+
+// Report sends a progress report with message and percentage. The
+// percentage is ignored if Begin did not set one, and is clamped between the
+// last reported value and 100.
+func (p *ProgressReporter) Report(ctx context.Context, message string, percentage int) error {
+	if p.IsCancelled() {
+		return ErrProgressCancelled
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.state {
+	case progressIdle:
+		return ErrProgressNotStarted
+	case progressEnded:
+		return ErrProgressEnded
+	}
+
+	report := WorkDoneProgressReport{Message: message}
+	if p.hasPercentage {
+		p.percentage = p.clamp(percentage)
+		report.Percentage = uint32Ptr(p.percentage)
+	}
+
+	return p.send(ctx, report)
+}
+
+// End ends the progress with an optional final message. Calling End more
+// than once is a no-op.
+func (p *ProgressReporter) End(ctx context.Context, message string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.state {
+	case progressIdle:
+		return ErrProgressNotStarted
+	case progressEnded:
+		return nil
+	}
+
+	p.state = progressEnded
+	return p.send(ctx, WorkDoneProgressEnd{Message: message})
+}
+
+// Cancel marks the progress as cancelled by the client.
+func (p *ProgressReporter) Cancel() {
+	p.cancelOnce.Do(func() {
+		close(p.cancelled)
+	})
+}
+
+// Cancelled returns a channel that is closed once the progress is cancelled.
+func (p *ProgressReporter) Cancelled() <-chan struct{} {
+	return p.cancelled
+}
+
+// IsCancelled reports whether the progress was cancelled.
+func (p *ProgressReporter) IsCancelled() bool {
+	select {
+	case <-p.cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *ProgressReporter) send(ctx context.Context, value WorkDoneProgressValue) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.notify(ctx, MethodProgress, ProgressParams{Token: p.token, Value: value})
+}
+
+func (p *ProgressReporter) clamp(percentage int) uint32 {
+	if percentage > 100 {
+		percentage = 100
+	}
+	if percentage < int(p.percentage) {
+		return p.percentage
+	}
+	return uint32(percentage)
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
@@ -0,0 +1,155 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+type recordedNotification struct {
+	method string
+	params string
+}
+
+func newRecordingNotify(t *testing.T) (protocol.NotifyFunc, *[]recordedNotification) {
+	t.Helper()
+
+	var sent []recordedNotification
+	notify := func(ctx context.Context, method string, params any) error {
+		data, err := json.Marshal(params)
+		if err != nil {
+			t.Fatalf("marshal params failed: %v", err)
+		}
+		sent = append(sent, recordedNotification{method: method, params: string(data)})
+		return nil
+	}
+	return notify, &sent
+}
+
+func Test_ProgressReporter_BeginReportEnd(t *testing.T) {
+	notify, sent := newRecordingNotify(t)
+	reporter := protocol.NewProgressReporter(protocol.StringProgressToken("index"), notify)
+	ctx := context.Background()
+
+	zero := uint32(0)
+	if err := reporter.Begin(ctx, protocol.WorkDoneProgressBegin{Title: "Indexing", Cancellable: true, Percentage: &zero}); err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	for _, percentage := range []int{40, 20, 150, -5} {
+		if err := reporter.Report(ctx, "", percentage); err != nil {
+			t.Fatalf("report failed: %v", err)
+		}
+	}
+	if err := reporter.End(ctx, "done"); err != nil {
+		t.Fatalf("end failed: %v", err)
+	}
+
+	expected := []string{
+		`{"token":"index","value":{"kind":"begin","title":"Indexing","cancellable":true,"percentage":0}}`,
+		`{"token":"index","value":{"kind":"report","percentage":40}}`,
+		`{"token":"index","value":{"kind":"report","percentage":40}}`,
+		`{"token":"index","value":{"kind":"report","percentage":100}}`,
+		`{"token":"index","value":{"kind":"report","percentage":100}}`,
+		`{"token":"index","value":{"kind":"end","message":"done"}}`,
+	}
+
+	if len(*sent) != len(expected) {
+		t.Fatalf("expected %d notifications, got %d", len(expected), len(*sent))
+	}
+	for i, n := range *sent {
+		if n.method != protocol.MethodProgress {
+			t.Fatalf("expected method %q, got %q", protocol.MethodProgress, n.method)
+		}
+		if n.params != expected[i] {
+			t.Fatalf("notification %d: expected %s, got %s", i, expected[i], n.params)
+		}
+	}
+}
+
+func Test_ProgressReporter_NoPercentageWithoutBeginPercentage(t *testing.T) {
+	notify, sent := newRecordingNotify(t)
+	reporter := protocol.NewProgressReporter(protocol.NumberProgressToken(1), notify)
+	ctx := context.Background()
+
+	if err := reporter.Begin(ctx, protocol.WorkDoneProgressBegin{Title: "Indexing"}); err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	if err := reporter.Report(ctx, "app/", 50); err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+
+	if got := (*sent)[1].params; got != `{"token":1,"value":{"kind":"report","message":"app/"}}` {
+		t.Fatalf("unexpected report: %s", got)
+	}
+}
+
+func Test_ProgressReporter_Ordering(t *testing.T) {
+	notify, _ := newRecordingNotify(t)
+	reporter := protocol.NewProgressReporter(protocol.NumberProgressToken(1), notify)
+	ctx := context.Background()
+
+	if err := reporter.Report(ctx, "", 10); !errors.Is(err, protocol.ErrProgressNotStarted) {
+		t.Fatalf("expected ErrProgressNotStarted, got %v", err)
+	}
+
+	if err := reporter.Begin(ctx, protocol.WorkDoneProgressBegin{Title: "Indexing"}); err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	if err := reporter.End(ctx, ""); err != nil {
+		t.Fatalf("end failed: %v", err)
+	}
+
+	if err := reporter.Report(ctx, "", 10); !errors.Is(err, protocol.ErrProgressEnded) {
+		t.Fatalf("expected ErrProgressEnded, got %v", err)
+	}
+	if err := reporter.End(ctx, ""); err != nil {
+		t.Fatalf("expected second end to be a no-op, got %v", err)
+	}
+}
+
+func Test_ProgressReporter_Cancel(t *testing.T) {
+	notify, sent := newRecordingNotify(t)
+	reporter := protocol.NewProgressReporter(protocol.NumberProgressToken(1), notify)
+	ctx := context.Background()
+
+	if err := reporter.Begin(ctx, protocol.WorkDoneProgressBegin{Title: "Indexing", Cancellable: true}); err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+
+	reporter.Cancel()
+	reporter.Cancel()
+
+	select {
+	case <-reporter.Cancelled():
+	default:
+		t.Fatalf("expected cancelled channel to be closed")
+	}
+
+	if err := reporter.Report(ctx, "", 10); !errors.Is(err, protocol.ErrProgressCancelled) {
+		t.Fatalf("expected ErrProgressCancelled, got %v", err)
+	}
+	if err := reporter.End(ctx, "cancelled"); err != nil {
+		t.Fatalf("end after cancel failed: %v", err)
+	}
+	if len(*sent) != 2 {
+		t.Fatalf("expected begin and end notifications, got %d", len(*sent))
+	}
+}
+
+func Test_ProgressReporter_ContextDone(t *testing.T) {
+	notify, sent := newRecordingNotify(t)
+	reporter := protocol.NewProgressReporter(protocol.NumberProgressToken(1), notify)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := reporter.Begin(ctx, protocol.WorkDoneProgressBegin{Title: "Indexing"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("expected no notifications, got %d", len(*sent))
+	}
+}
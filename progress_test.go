@@ -34,3 +34,72 @@ func Test_Progress_StructsUnmarshalValidJSON(t *testing.T) {
 		t.Fatalf("unmarshal WorkDoneProgressCancelParams failed: %v", err)
 	}
 }
+
+func Test_Progress_TokenMarshal(t *testing.T) {
+	params := protocol.WorkDoneProgressCreateParams{Token: protocol.StringProgressToken("index")}
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != `{"token":"index"}` {
+		t.Fatalf("unexpected payload: %s", data)
+	}
+
+	token := protocol.NumberProgressToken(7)
+	if token.String() != "7" {
+		t.Fatalf("expected token string 7, got %q", token.String())
+	}
+}
+
+func Test_Progress_ValueMarshalKind(t *testing.T) {
+	percentage := uint32(10)
+	tests := []struct {
+		value    protocol.WorkDoneProgressValue
+		expected string
+	}{
+		{protocol.WorkDoneProgressBegin{Title: "Indexing", Percentage: &percentage}, `{"kind":"begin","title":"Indexing","percentage":10}`},
+		{protocol.WorkDoneProgressReport{Message: "app/"}, `{"kind":"report","message":"app/"}`},
+		{protocol.WorkDoneProgressEnd{}, `{"kind":"end"}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+		if string(data) != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, data)
+		}
+	}
+}
+
+func Test_Progress_ParamsUnmarshalWorkDoneProgress(t *testing.T) {
+	data := []byte(`{"token":"index","value":{"kind":"report","message":"vendor/","percentage":50}}`)
+
+	var params protocol.ProgressParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if params.Token.String() != "index" {
+		t.Fatalf("expected token index, got %q", params.Token.String())
+	}
+
+	value, err := params.WorkDoneProgress()
+	if err != nil {
+		t.Fatalf("decode work done progress failed: %v", err)
+	}
+
+	report, ok := value.(protocol.WorkDoneProgressReport)
+	if !ok {
+		t.Fatalf("expected WorkDoneProgressReport, got %T", value)
+	}
+	if report.Message != "vendor/" || report.Percentage == nil || *report.Percentage != 50 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	params.Value = json.RawMessage(`{"kind":"unknown"}`)
+	if _, err := params.WorkDoneProgress(); err == nil {
+		t.Fatalf("expected error for unknown kind")
+	}
+}
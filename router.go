@@ -0,0 +1,352 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// Request - An incoming request or notification, independent of the
+// transport it was received on.
+type Request struct {
+	// The request id. Nil for notifications.
+	ID *RequestID
+
+	// The method to be invoked.
+	Method string
+
+	// The method's params, as raw JSON.
+	Params json.RawMessage
+}
+
+// IsNotification reports whether the request is a notification,
+// meaning no response is expected.
+func (r *Request) IsNotification() bool {
+	return r.ID == nil
+}
+
+// Handler handles incoming requests and notifications.
+//
+// Errors should be a *ResponseError when a specific error code is to
+// be sent to the client. For notifications the result is discarded.
+type Handler interface {
+	Handle(ctx context.Context, req *Request) (any, error)
+}
+
+// HandlerFunc is an adapter to allow the use of ordinary functions as handlers.
+type HandlerFunc func(ctx context.Context, req *Request) (any, error)
+
+func (f HandlerFunc) Handle(ctx context.Context, req *Request) (any, error) {
+	return f(ctx, req)
+}
+
+// Router dispatches requests to handlers registered by method name.
+//
+// Typed handlers decode the params and encode the result, so the result
+// returned from Handle is a json.RawMessage. Unknown methods result in a
+// RPCMethodNotFound error and params that fail to decode in a
+// RPCInvalidParams error.
+type Router struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewRouter creates an empty router.
+func NewRouter() *Router {
+	return &Router{
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register registers a raw handler for method, replacing any previous handler.
+func (r *Router) Register(method string, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[method] = h
+}
+
+// Handle dispatches req to the handler registered for its method.
+func (r *Router) Handle(ctx context.Context, req *Request) (any, error) {
+	r.mu.RLock()
+	h, ok := r.handlers[req.Method]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, NewResponseError(RPCMethodNotFound, "method %q not found", req.Method)
+	}
+
+	return h.Handle(ctx, req)
+}
+
+// HandleRequest registers a typed request handler for method.
+func HandleRequest[P, R any](r *Router, method string, fn func(ctx context.Context, params P) (R, error)) {
+	r.Register(method, HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+		var params P
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		result, err := fn(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, NewResponseError(RPCInternalError, "failed to encode %s result: %s", req.Method, err.Error())
+		}
+
+		return json.RawMessage(data), nil
+	}))
+}
+
+// HandleNotification registers a typed notification handler for method.
+func HandleNotification[P any](r *Router, method string, fn func(ctx context.Context, params P) error) {
+	r.Register(method, HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+		var params P
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		return nil, fn(ctx, params)
+	}))
+}
+
+func decodeParams(req *Request, v any) error {
+	if len(req.Params) == 0 || string(req.Params) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(req.Params, v); err != nil {
+		return NewResponseError(RPCInvalidParams, "invalid params for %s: %s", req.Method, err.Error())
+	}
+
+	return nil
+}
+
+// HandleInitialize registers a handler for the `initialize` request.
+func (r *Router) HandleInitialize(fn func(ctx context.Context, params InitializeParams) (InitializeResult, error)) {
+	HandleRequest(r, MethodInitialize, fn)
+}
+
+// HandleInitialized registers a handler for the `initialized` notification.
+func (r *Router) HandleInitialized(fn func(ctx context.Context, params InitializedParams) error) {
+	HandleNotification(r, MethodInitialized, fn)
+}
+
+// HandleShutdown registers a handler for the `shutdown` request.
+func (r *Router) HandleShutdown(fn func(ctx context.Context) error) {
+	r.Register(MethodShutdown, HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+		if err := fn(ctx); err != nil {
+			return nil, err
+		}
+		return json.RawMessage("null"), nil
+	}))
+}
+
+// HandleExit registers a handler for the `exit` notification.
+func (r *Router) HandleExit(fn func(ctx context.Context) error) {
+	r.Register(MethodExit, HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+		return nil, fn(ctx)
+	}))
+}
+
+// HandleCancelRequest registers a handler for the `$/cancelRequest` notification.
+func (r *Router) HandleCancelRequest(fn func(ctx context.Context, params CancelParams) error) {
+	HandleNotification(r, MethodCancelRequest, fn)
+}
+
+// HandleWorkDoneProgressCancel registers a handler for the `window/workDoneProgress/cancel` notification.
+func (r *Router) HandleWorkDoneProgressCancel(fn func(ctx context.Context, params WorkDoneProgressCancelParams) error) {
+	HandleNotification(r, MethodWindowWorkDoneProgressCancel, fn)
+}
+
+// HandleProgress registers a handler for the `$/progress` notification.
+func (r *Router) HandleProgress(fn func(ctx context.Context, params ProgressParams) error) {
+	HandleNotification(r, MethodProgress, fn)
+}
+
+// HandleDidOpen registers a handler for the `textDocument/didOpen` notification.
+func (r *Router) HandleDidOpen(fn func(ctx context.Context, params DidOpenTextDocumentParams) error) {
+	HandleNotification(r, MethodTextDocumentDidOpen, fn)
+}
+
+// HandleDidChange registers a handler for the `textDocument/didChange` notification.
+func (r *Router) HandleDidChange(fn func(ctx context.Context, params DidChangeTextDocumentParams) error) {
+	HandleNotification(r, MethodTextDocumentDidChange, fn)
+}
+
+// HandleDidClose registers a handler for the `textDocument/didClose` notification.
+func (r *Router) HandleDidClose(fn func(ctx context.Context, params DidCloseTextDocumentParams) error) {
+	HandleNotification(r, MethodTextDocumentDidClose, fn)
+}
+
+// HandleDidSave registers a handler for the `textDocument/didSave` notification.
+func (r *Router) HandleDidSave(fn func(ctx context.Context, params DidSaveTextDocumentParams) error) {
+	HandleNotification(r, MethodTextDocumentDidSave, fn)
+}
+
+// HandleHover registers a handler for the `textDocument/hover` request.
+func (r *Router) HandleHover(fn func(ctx context.Context, params HoverParams) (HoverResult, error)) {
+	HandleRequest(r, MethodTextDocumentHover, fn)
+}
+
+// HandleCompletion registers a handler for the `textDocument/completion` request.
+func (r *Router) HandleCompletion(fn func(ctx context.Context, params CompletionParams) (CompletionResponse, error)) {
+	HandleRequest(r, MethodTextDocumentCompletion, fn)
+}
+
+// HandleDefinition registers a handler for the `textDocument/definition` request.
+func (r *Router) HandleDefinition(fn func(ctx context.Context, params DefinitionParams) (DefinitionResponse, error)) {
+	HandleRequest(r, MethodTextDocumentDefinition, fn)
+}
+
+// HandleSignatureHelp registers a handler for the `textDocument/signatureHelp` request.
+func (r *Router) HandleSignatureHelp(fn func(ctx context.Context, params SignatureHelpParams) (*SignatureHelp, error)) {
+	HandleRequest(r, MethodTextDocumentSignatureHelp, fn)
+}
+
+// HandleDeclaration registers a handler for the `textDocument/declaration` request.
+func (r *Router) HandleDeclaration(fn func(ctx context.Context, params DeclarationParams) (DeclarationResponse, error)) {
+	HandleRequest(r, MethodTextDocumentDeclaration, fn)
+}
+
+// HandleTypeDefinition registers a handler for the `textDocument/typeDefinition` request.
+func (r *Router) HandleTypeDefinition(fn func(ctx context.Context, params TypeDefinitionParams) (TypeDefinitionResponse, error)) {
+	HandleRequest(r, MethodTextDocumentTypeDefinition, fn)
+}
+
+// HandleReferences registers a handler for the `textDocument/references` request.
+func (r *Router) HandleReferences(fn func(ctx context.Context, params ReferenceParams) ([]Location, error)) {
+	HandleRequest(r, MethodTextDocumentReferences, fn)
+}
+
+// HandleDocumentHighlight registers a handler for the `textDocument/documentHighlight` request.
+func (r *Router) HandleDocumentHighlight(fn func(ctx context.Context, params DocumentHighlightParams) ([]DocumentHighlight, error)) {
+	HandleRequest(r, MethodTextDocumentDocumentHighlight, fn)
+}
+
+// HandleImplementation registers a handler for the `textDocument/implementation` request.
+func (r *Router) HandleImplementation(fn func(ctx context.Context, params ImplementationParams) (ImplementationResponse, error)) {
+	HandleRequest(r, MethodTextDocumentImplementation, fn)
+}
+
+// HandleRename registers a handler for the `textDocument/rename` request.
+func (r *Router) HandleRename(fn func(ctx context.Context, params RenameParams) (*WorkspaceEdit, error)) {
+	HandleRequest(r, MethodTextDocumentRename, fn)
+}
+
+// HandlePrepareRename registers a handler for the `textDocument/prepareRename` request.
+func (r *Router) HandlePrepareRename(fn func(ctx context.Context, params PrepareRenameParams) (PrepareRenameResult, error)) {
+	HandleRequest(r, MethodTextDocumentPrepareRename, fn)
+}
+
+// HandleFormatting registers a handler for the `textDocument/formatting` request.
+func (r *Router) HandleFormatting(fn func(ctx context.Context, params DocumentFormattingParams) ([]TextEdit, error)) {
+	HandleRequest(r, MethodTextDocumentFormatting, fn)
+}
+
+// HandleRangeFormatting registers a handler for the `textDocument/rangeFormatting` request.
+func (r *Router) HandleRangeFormatting(fn func(ctx context.Context, params DocumentRangeFormattingParams) ([]TextEdit, error)) {
+	HandleRequest(r, MethodTextDocumentRangeFormatting, fn)
+}
+
+// HandleRangesFormatting registers a handler for the `textDocument/rangesFormatting` request.
+func (r *Router) HandleRangesFormatting(fn func(ctx context.Context, params DocumentRangesFormattingParams) ([]TextEdit, error)) {
+	HandleRequest(r, MethodTextDocumentRangesFormatting, fn)
+}
+
+// HandleOnTypeFormatting registers a handler for the `textDocument/onTypeFormatting` request.
+func (r *Router) HandleOnTypeFormatting(fn func(ctx context.Context, params DocumentOnTypeFormattingParams) ([]TextEdit, error)) {
+	HandleRequest(r, MethodTextDocumentOnTypeFormatting, fn)
+}
+
+// HandleDocumentSymbol registers a handler for the `textDocument/documentSymbol` request.
+func (r *Router) HandleDocumentSymbol(fn func(ctx context.Context, params DocumentSymbolParams) (DocumentSymbolResponse, error)) {
+	HandleRequest(r, MethodTextDocumentDocumentSymbol, fn)
+}
+
+// HandleWorkspaceSymbol registers a handler for the `workspace/symbol` request.
+func (r *Router) HandleWorkspaceSymbol(fn func(ctx context.Context, params WorkspaceSymbolParams) (WorkspaceSymbolResponse, error)) {
+	HandleRequest(r, MethodWorkspaceSymbol, fn)
+}
+
+// HandleWorkspaceSymbolResolve registers a handler for the `workspaceSymbol/resolve` request.
+func (r *Router) HandleWorkspaceSymbolResolve(fn func(ctx context.Context, params WorkspaceSymbol) (WorkspaceSymbol, error)) {
+	HandleRequest(r, MethodWorkspaceSymbolResolve, fn)
+}
+
+// HandleCodeAction registers a handler for the `textDocument/codeAction` request.
+func (r *Router) HandleCodeAction(fn func(ctx context.Context, params CodeActionParams) ([]CodeAction, error)) {
+	HandleRequest(r, MethodTextDocumentCodeAction, fn)
+}
+
+// HandleCodeLens registers a handler for the `textDocument/codeLens` request.
+func (r *Router) HandleCodeLens(fn func(ctx context.Context, params CodeLensParams) ([]CodeLens, error)) {
+	HandleRequest(r, MethodTextDocumentCodeLens, fn)
+}
+
+// HandleCodeLensResolve registers a handler for the `codeLens/resolve` request.
+func (r *Router) HandleCodeLensResolve(fn func(ctx context.Context, params CodeLens) (CodeLens, error)) {
+	HandleRequest(r, MethodCodeLensResolve, fn)
+}
+
+// HandleCodeLensRefresh registers a handler for the `workspace/codeLens/refresh` request.
+func (r *Router) HandleCodeLensRefresh(fn func(ctx context.Context) error) {
+	r.Register(MethodWorkspaceCodeLensRefresh, HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+		if err := fn(ctx); err != nil {
+			return nil, err
+		}
+		return json.RawMessage("null"), nil
+	}))
+}
+
+// HandleDocumentLink registers a handler for the `textDocument/documentLink` request.
+func (r *Router) HandleDocumentLink(fn func(ctx context.Context, params DocumentLinkParams) ([]DocumentLink, error)) {
+	HandleRequest(r, MethodTextDocumentDocumentLink, fn)
+}
+
+// HandleDocumentLinkResolve registers a handler for the `documentLink/resolve` request.
+func (r *Router) HandleDocumentLinkResolve(fn func(ctx context.Context, params DocumentLink) (DocumentLink, error)) {
+	HandleRequest(r, MethodDocumentLinkResolve, fn)
+}
+
+// HandleDocumentColor registers a handler for the `textDocument/documentColor` request.
+func (r *Router) HandleDocumentColor(fn func(ctx context.Context, params DocumentColorParams) ([]ColorInformation, error)) {
+	HandleRequest(r, MethodTextDocumentDocumentColor, fn)
+}
+
+// HandleColorPresentation registers a handler for the `textDocument/colorPresentation` request.
+func (r *Router) HandleColorPresentation(fn func(ctx context.Context, params ColorPresentationParams) ([]ColorPresentation, error)) {
+	HandleRequest(r, MethodTextDocumentColorPresentation, fn)
+}
+
+// HandleFoldingRange registers a handler for the `textDocument/foldingRange` request.
+func (r *Router) HandleFoldingRange(fn func(ctx context.Context, params FoldingRangeParams) ([]FoldingRange, error)) {
+	HandleRequest(r, MethodTextDocumentFoldingRange, fn)
+}
+
+// HandleFoldingRangeRefresh registers a handler for the `workspace/foldingRange/refresh` request.
+func (r *Router) HandleFoldingRangeRefresh(fn func(ctx context.Context) error) {
+	r.Register(MethodWorkspaceFoldingRangeRefresh, HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+		if err := fn(ctx); err != nil {
+			return nil, err
+		}
+		return json.RawMessage("null"), nil
+	}))
+}
+
+// HandlePublishDiagnostics registers a handler for the `textDocument/publishDiagnostics` notification.
+func (r *Router) HandlePublishDiagnostics(fn func(ctx context.Context, params PublishDiagnosticsParams) error) {
+	HandleNotification(r, MethodTextDocumentPublishDiagnostics, fn)
+}
+
+// HandleDiagnostic registers a handler for the `textDocument/diagnostic` request.
+func (r *Router) HandleDiagnostic(fn func(ctx context.Context, params DocumentDiagnosticParams) (DocumentDiagnosticReport, error)) {
+	HandleRequest(r, MethodTextDocumentDiagnostic, fn)
+}
+
+// HandleInlayHint registers a handler for the `textDocument/inlayHint` request.
+func (r *Router) HandleInlayHint(fn func(ctx context.Context, params InlayHintParams) (InlayHintResponse, error)) {
+	HandleRequest(r, MethodTextDocumentInlayHint, fn)
+}
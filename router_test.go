@@ -0,0 +1,128 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func newRequest(id int64, method string, params string) *protocol.Request {
+	req := &protocol.Request{Method: method}
+	if id > 0 {
+		reqID := protocol.NumberRequestID(id)
+		req.ID = &reqID
+	}
+	if params != "" {
+		req.Params = json.RawMessage(params)
+	}
+	return req
+}
+
+func Test_Router_DispatchesTypedRequest(t *testing.T) {
+	router := protocol.NewRouter()
+	router.HandleHover(func(ctx context.Context, params protocol.HoverParams) (protocol.HoverResult, error) {
+		if params.TextDocument.URI != "file:///tmp/a.php" || params.Position.Line != 3 {
+			t.Fatalf("unexpected HoverParams: %+v", params)
+		}
+		return protocol.HoverResult{Hover: &protocol.Hover{
+			Contents: protocol.MarkupContentOrMarkedString{
+				Markup: &protocol.MarkupContent{Kind: protocol.MarkupKindPlainText, Value: "hi"},
+			},
+		}}, nil
+	})
+
+	result, err := router.Handle(context.Background(), newRequest(1, protocol.MethodTextDocumentHover,
+		`{"textDocument":{"uri":"file:///tmp/a.php"},"position":{"line":3,"character":0}}`))
+	if err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+
+	raw, ok := result.(json.RawMessage)
+	if !ok {
+		t.Fatalf("expected json.RawMessage result, got %T", result)
+	}
+
+	if string(raw) != `{"contents":{"kind":"plaintext","value":"hi"}}` {
+		t.Fatalf("unexpected result: %s", raw)
+	}
+}
+
+func Test_Router_DispatchesTypedNotification(t *testing.T) {
+	router := protocol.NewRouter()
+
+	var opened protocol.DidOpenTextDocumentParams
+	router.HandleDidOpen(func(ctx context.Context, params protocol.DidOpenTextDocumentParams) error {
+		opened = params
+		return nil
+	})
+
+	result, err := router.Handle(context.Background(), newRequest(0, protocol.MethodTextDocumentDidOpen,
+		`{"textDocument":{"uri":"file:///tmp/a.php","languageId":"php","version":1,"text":"<?php"}}`))
+	if err != nil || result != nil {
+		t.Fatalf("expected nil result and error, got %v, %v", result, err)
+	}
+
+	if opened.TextDocument.Text != "<?php" {
+		t.Fatalf("unexpected DidOpenTextDocumentParams: %+v", opened)
+	}
+}
+
+func Test_Router_MethodNotFound(t *testing.T) {
+	router := protocol.NewRouter()
+
+	_, err := router.Handle(context.Background(), newRequest(1, "unknown/method", ""))
+
+	var rerr *protocol.ResponseError
+	if !errors.As(err, &rerr) || rerr.Code != protocol.RPCMethodNotFound {
+		t.Fatalf("expected method not found error, got %v", err)
+	}
+}
+
+func Test_Router_InvalidParams(t *testing.T) {
+	router := protocol.NewRouter()
+	router.HandleDefinition(func(ctx context.Context, params protocol.DefinitionParams) (protocol.DefinitionResponse, error) {
+		t.Fatalf("handler must not be called")
+		return protocol.DefinitionResponse{}, nil
+	})
+
+	_, err := router.Handle(context.Background(), newRequest(1, protocol.MethodTextDocumentDefinition, `{"position":"nope"}`))
+
+	var rerr *protocol.ResponseError
+	if !errors.As(err, &rerr) || rerr.Code != protocol.RPCInvalidParams {
+		t.Fatalf("expected invalid params error, got %v", err)
+	}
+}
+
+func Test_Router_MissingParamsDecodeToZeroValue(t *testing.T) {
+	router := protocol.NewRouter()
+
+	called := false
+	router.HandleInitialized(func(ctx context.Context, params protocol.InitializedParams) error {
+		called = true
+		return nil
+	})
+
+	if _, err := router.Handle(context.Background(), newRequest(0, protocol.MethodInitialized, "")); err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+
+	if !called {
+		t.Fatalf("expected initialized handler to be called")
+	}
+}
+
+func Test_Router_PassesHandlerErrors(t *testing.T) {
+	router := protocol.NewRouter()
+	want := protocol.NewResponseError(protocol.RPCContentModified, "modified")
+
+	protocol.HandleRequest(router, "custom/request", func(ctx context.Context, params map[string]int) (int, error) {
+		return 0, want
+	})
+
+	if _, err := router.Handle(context.Background(), newRequest(1, "custom/request", `{"a":1}`)); err != want {
+		t.Fatalf("expected handler error to be passed through, got %v", err)
+	}
+}
@@ -1,5 +1,31 @@
 package protocol
 
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Error codes defined by JSON-RPC 2.0.
+const (
+	// Invalid JSON was received by the server.
+	RPCParseError int64 = -32700
+
+	// The JSON sent is not a valid Request object.
+	RPCInvalidRequest int64 = -32600
+
+	// The method does not exist / is not available.
+	RPCMethodNotFound int64 = -32601
+
+	// Invalid method parameter(s).
+	RPCInvalidParams int64 = -32602
+
+	// Internal JSON-RPC error.
+	RPCInternalError int64 = -32603
+)
+
 // Range of the LSP code space
 const (
 	LspReservedErrorRangeStart = -32899
@@ -45,3 +71,93 @@ func IsLspRPCErrorCode(code int64) bool {
 
 	return code == RPCServerNotInitialized || code == RPCUnknownErrorCode
 }
+
+// ResponseError - The error object carried by a response message when a request failed.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#responseMessage
+type ResponseError struct {
+	// A number indicating the error type that occurred.
+	Code int64 `json:"code"`
+
+	// A string providing a short description of the error.
+	Message string `json:"message"`
+
+	// A primitive or structured value that contains additional
+	// information about the error. Can be omitted.
+	Data LSPAny `json:"data,omitempty"`
+}
+
+// NewResponseError creates a ResponseError with the given code and a formatted message.
+func NewResponseError(code int64, format string, args ...any) *ResponseError {
+	return &ResponseError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// RequestID - The id of a request message. Can be a string or a number.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#requestMessage
+type RequestID struct {
+	// Num is the numeric id, used when IsString is false.
+	Num int64
+
+	// Str is the string id, used when IsString is true.
+	Str string
+
+	// IsString reports whether the id is a string.
+	IsString bool
+}
+
+// NumberRequestID creates a numeric request id.
+func NumberRequestID(n int64) RequestID {
+	return RequestID{Num: n}
+}
+
+// StringRequestID creates a string request id.
+func StringRequestID(s string) RequestID {
+	return RequestID{Str: s, IsString: true}
+}
+
+func (id RequestID) String() string {
+	if id.IsString {
+		return id.Str
+	}
+	return strconv.FormatInt(id.Num, 10)
+}
+
+func (id RequestID) MarshalJSON() ([]byte, error) {
+	if id.IsString {
+		return json.Marshal(id.Str)
+	}
+	return json.Marshal(id.Num)
+}
+
+func (id *RequestID) UnmarshalJSON(data []byte) error {
+	*id = RequestID{}
+
+	// Unmarshalling null into a number succeeds without a change, which would
+	// silently decode to the id 0.
+	if string(data) == "null" {
+		return errors.New("invalid request id: null")
+	}
+
+	if err := json.Unmarshal(data, &id.Num); err == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &id.Str); err == nil {
+		id.IsString = true
+		return nil
+	}
+
+	return errors.New("invalid request id: not a string or integer")
+}
+
+// NotifyFunc sends a notification with the given method and params to the
+// other end of the connection. jsonrpc.Conn.Notify satisfies it.
+type NotifyFunc func(ctx context.Context, method string, params any) error
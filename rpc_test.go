@@ -1,6 +1,7 @@
 package protocol_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/laravel-ls/protocol"
@@ -36,3 +37,58 @@ func Test_Rpc_IsLspRPCErrorCode(t *testing.T) {
 		t.Errorf("-32002 is a valid code, but function returned false")
 	}
 }
+
+func Test_Rpc_ResponseErrorJSONRoundTrip(t *testing.T) {
+	original := protocol.NewResponseError(protocol.RPCMethodNotFound, "method %q not found", "foo/bar")
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	if string(data) != `{"code":-32601,"message":"method \"foo/bar\" not found"}` {
+		t.Fatalf("unexpected JSON: %s", string(data))
+	}
+
+	var decoded protocol.ResponseError
+	if err := json.Unmarshal([]byte(`{"code":-32801,"message":"modified","data":{"uri":"file:///a.php"}}`), &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if decoded.Code != protocol.RPCContentModified || decoded.Message != "modified" || decoded.Data == nil {
+		t.Fatalf("unexpected ResponseError: %+v", decoded)
+	}
+
+	if decoded.Error() == "" {
+		t.Fatalf("expected non-empty error string")
+	}
+}
+
+func Test_Rpc_RequestIDUnmarshalNumberAndString(t *testing.T) {
+	var num protocol.RequestID
+	if err := json.Unmarshal([]byte(`42`), &num); err != nil {
+		t.Fatalf("unmarshal numeric RequestID failed: %v", err)
+	}
+	if num.IsString || num.Num != 42 || num.String() != "42" {
+		t.Fatalf("unexpected numeric RequestID: %+v", num)
+	}
+
+	var str protocol.RequestID
+	if err := json.Unmarshal([]byte(`"abc"`), &str); err != nil {
+		t.Fatalf("unmarshal string RequestID failed: %v", err)
+	}
+	if !str.IsString || str.Str != "abc" || str != protocol.StringRequestID("abc") {
+		t.Fatalf("unexpected string RequestID: %+v", str)
+	}
+
+	if err := json.Unmarshal([]byte(`{}`), &str); err == nil {
+		t.Fatalf("expected error for object RequestID")
+	}
+}
+
+func Test_Rpc_RequestIDRejectsNull(t *testing.T) {
+	var params protocol.CancelParams
+	if err := json.Unmarshal([]byte(`{"id":null}`), &params); err == nil {
+		t.Fatalf("expected error for null id, got %+v", params.Id)
+	}
+}
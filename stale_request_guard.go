@@ -0,0 +1,136 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// StaleRequestGuard is a Handler that detects requests whose document
+// changed while they were being handled.
+//
+// It records the state of the request's `textDocument` when the request
+// starts. If a `textDocument/didChange` or `textDocument/didClose` for that
+// document arrives before the handler finishes, the request is stale: it
+// fails with RPCContentModified if the client retries the method on that
+// error, and otherwise its result is passed through, since a result
+// computed against an older state may still be useful.
+//
+// The guard learns the client's `retryOnContentModified` list from the
+// `initialize` request it passes on.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#clientCapabilities
+type StaleRequestGuard struct {
+	next Handler
+
+	mu      sync.Mutex
+	support ClientSupport
+	changes map[DocumentURI]*documentChanges
+}
+
+// documentChanges counts the changes of a document while requests for it
+// are in flight. It is dropped once no request uses it anymore.
+type documentChanges struct {
+	inFlight int
+	count    uint64
+}
+
+// NewStaleRequestGuard creates a stale request guard in front of next.
+func NewStaleRequestGuard(next Handler) *StaleRequestGuard {
+	return &StaleRequestGuard{
+		next:    next,
+		changes: make(map[DocumentURI]*documentChanges),
+	}
+}
+
+// SetClientSupport sets the client capabilities used to decide whether a
+// stale request fails. It is only needed if the guard does not see the
+// `initialize` request.
+func (g *StaleRequestGuard) SetClientSupport(support ClientSupport) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.support = support
+}
+
+// TrackedDocuments returns the number of documents with requests in flight.
+func (g *StaleRequestGuard) TrackedDocuments() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.changes)
+}
+
+func (g *StaleRequestGuard) Handle(ctx context.Context, req *Request) (any, error) {
+	switch req.Method {
+	case MethodInitialize:
+		var params InitializeParams
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			g.SetClientSupport(NewClientSupport(params))
+		}
+		return g.next.Handle(ctx, req)
+	case MethodTextDocumentDidChange, MethodTextDocumentDidClose:
+		if uri := requestDocumentURI(req); uri != "" {
+			g.mu.Lock()
+			if changes, ok := g.changes[uri]; ok {
+				changes.count++
+			}
+			g.mu.Unlock()
+		}
+		return g.next.Handle(ctx, req)
+	}
+
+	if req.IsNotification() {
+		return g.next.Handle(ctx, req)
+	}
+
+	uri := requestDocumentURI(req)
+	if uri == "" {
+		return g.next.Handle(ctx, req)
+	}
+
+	g.mu.Lock()
+	changes, ok := g.changes[uri]
+	if !ok {
+		changes = &documentChanges{}
+		g.changes[uri] = changes
+	}
+	changes.inFlight++
+	started := changes.count
+	g.mu.Unlock()
+
+	result, err := g.next.Handle(ctx, req)
+
+	g.mu.Lock()
+	stale := changes.count != started
+	changes.inFlight--
+	if changes.inFlight == 0 {
+		delete(g.changes, uri)
+	}
+	retry := g.support.RetryOnContentModified(req.Method)
+	g.mu.Unlock()
+
+	if err != nil {
+		return result, err
+	}
+
+	if stale && retry {
+		return nil, NewResponseError(RPCContentModified, "content modified: %s", uri)
+	}
+	return result, nil
+}
+
+// requestDocumentURI returns the `textDocument.uri` of the request params, or
+// an empty string if there is none.
+func requestDocumentURI(req *Request) DocumentURI {
+	var params struct {
+		TextDocument struct {
+			URI DocumentURI `json:"uri"`
+		} `json:"textDocument"`
+	}
+
+	if len(req.Params) == 0 || json.Unmarshal(req.Params, &params) != nil {
+		return ""
+	}
+	return params.TextDocument.URI
+}
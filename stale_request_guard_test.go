@@ -0,0 +1,133 @@
+package protocol_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/laravel-ls/protocol"
+)
+
+const staleHoverParams = `{"textDocument":{"uri":"file:///a.php"},"position":{"line":0,"character":0}}`
+
+// newStaleGuard returns a guard whose hover handler blocks until release is
+// closed, after signalling on started.
+func newStaleGuard(t *testing.T) (*protocol.StaleRequestGuard, chan struct{}, chan struct{}) {
+	t.Helper()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	router := protocol.NewRouter()
+	router.HandleHover(func(ctx context.Context, params protocol.HoverParams) (protocol.HoverResult, error) {
+		started <- struct{}{}
+		<-release
+		return protocol.HoverResult{Null: true}, nil
+	})
+	router.Register("initialize", protocol.HandlerFunc(func(ctx context.Context, req *protocol.Request) (any, error) {
+		return protocol.InitializeResult{}, nil
+	}))
+	router.HandleDidChange(func(ctx context.Context, params protocol.DidChangeTextDocumentParams) error {
+		return nil
+	})
+	return protocol.NewStaleRequestGuard(router), started, release
+}
+
+func runStaleHover(t *testing.T, guard *protocol.StaleRequestGuard, started, release chan struct{}, change string) (any, error) {
+	t.Helper()
+
+	type response struct {
+		result any
+		err    error
+	}
+	done := make(chan response, 1)
+	go func() {
+		result, err := guard.Handle(context.Background(), newRequest(2, protocol.MethodTextDocumentHover, staleHoverParams))
+		done <- response{result, err}
+	}()
+	<-started
+
+	if change != "" {
+		if _, err := guard.Handle(context.Background(), newRequest(0, protocol.MethodTextDocumentDidChange, change)); err != nil {
+			t.Fatalf("didChange failed: %v", err)
+		}
+	}
+	close(release)
+
+	select {
+	case r := <-done:
+		return r.result, r.err
+	case <-time.After(time.Second):
+		t.Fatalf("hover did not finish")
+		return nil, nil
+	}
+}
+
+func initializeStaleGuard(t *testing.T, guard *protocol.StaleRequestGuard, capabilities string) {
+	t.Helper()
+
+	if _, err := guard.Handle(context.Background(), newRequest(1, protocol.MethodInitialize, `{"capabilities":`+capabilities+`}`)); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+}
+
+func Test_StaleRequestGuard_ContentModifiedWhenClientRetries(t *testing.T) {
+	guard, started, release := newStaleGuard(t)
+	initializeStaleGuard(t, guard, `{"general":{"staleRequestSupport":{"cancel":true,"retryOnContentModified":["textDocument/hover"]}}}`)
+
+	_, err := runStaleHover(t, guard, started, release, `{"textDocument":{"uri":"file:///a.php","version":2},"contentChanges":[{"text":"x"}]}`)
+	expectErrorCode(t, err, protocol.RPCContentModified)
+}
+
+func Test_StaleRequestGuard_PassesResultWithoutRetry(t *testing.T) {
+	guard, started, release := newStaleGuard(t)
+	initializeStaleGuard(t, guard, `{}`)
+
+	result, err := runStaleHover(t, guard, started, release, `{"textDocument":{"uri":"file:///a.php","version":2},"contentChanges":[{"text":"x"}]}`)
+	if err != nil || result == nil {
+		t.Fatalf("expected stale result to pass through, got %v, %v", result, err)
+	}
+}
+
+func Test_StaleRequestGuard_OtherDocumentChange(t *testing.T) {
+	guard, started, release := newStaleGuard(t)
+	initializeStaleGuard(t, guard, `{"general":{"staleRequestSupport":{"cancel":true,"retryOnContentModified":["textDocument/hover"]}}}`)
+
+	result, err := runStaleHover(t, guard, started, release, `{"textDocument":{"uri":"file:///b.php","version":2},"contentChanges":[{"text":"x"}]}`)
+	if err != nil || result == nil {
+		t.Fatalf("expected result for unchanged document, got %v, %v", result, err)
+	}
+}
+
+func Test_StaleRequestGuard_UnchangedDocument(t *testing.T) {
+	guard, started, release := newStaleGuard(t)
+	guard.SetClientSupport(newClientSupport(t, `{"general":{"staleRequestSupport":{"cancel":true,"retryOnContentModified":["textDocument/hover"]}}}`))
+
+	result, err := runStaleHover(t, guard, started, release, "")
+	if err != nil || result == nil {
+		t.Fatalf("expected result for unchanged document, got %v, %v", result, err)
+	}
+}
+
+func Test_StaleRequestGuard_ForgetsIdleDocuments(t *testing.T) {
+	guard, started, release := newStaleGuard(t)
+	initializeStaleGuard(t, guard, `{"general":{"staleRequestSupport":{"cancel":true,"retryOnContentModified":["textDocument/hover"]}}}`)
+
+	for i := 0; i < 3; i++ {
+		uri := fmt.Sprintf("file:///%d.php", i)
+		if _, err := guard.Handle(context.Background(), newRequest(0, protocol.MethodTextDocumentDidChange,
+			`{"textDocument":{"uri":"`+uri+`","version":2},"contentChanges":[{"text":"x"}]}`)); err != nil {
+			t.Fatalf("didChange failed: %v", err)
+		}
+	}
+	if n := guard.TrackedDocuments(); n != 0 {
+		t.Fatalf("expected changes without requests in flight not to be tracked, got %d", n)
+	}
+
+	_, err := runStaleHover(t, guard, started, release, `{"textDocument":{"uri":"file:///a.php","version":2},"contentChanges":[{"text":"x"}]}`)
+	expectErrorCode(t, err, protocol.RPCContentModified)
+
+	if n := guard.TrackedDocuments(); n != 0 {
+		t.Fatalf("expected finished requests to release their document, got %d", n)
+	}
+}
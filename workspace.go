@@ -1,5 +1,7 @@
 package protocol
 
+import "encoding/json"
+
 // WorkspaceEdit - Represents changes to many resources managed in the workspace.
 // The edit should either provide `changes` or `documentChanges`. If the client can handle versioned document edits
 // and if `documentChanges` are present, the latter are preferred over `changes`.
@@ -25,6 +27,35 @@ type WorkspaceEdit struct {
 	ChangeAnnotations map[string]ChangeAnnotation `json:"changeAnnotations,omitempty"`
 }
 
+func (w *WorkspaceEdit) UnmarshalJSON(data []byte) error {
+	type workspaceEdit WorkspaceEdit
+
+	var temp struct {
+		workspaceEdit
+		DocumentChanges []json.RawMessage `json:"documentChanges,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	*w = WorkspaceEdit(temp.workspaceEdit)
+	w.DocumentChanges = nil
+
+	if temp.DocumentChanges != nil {
+		w.DocumentChanges = make([]DocumentChangeOperation, 0, len(temp.DocumentChanges))
+		for _, raw := range temp.DocumentChanges {
+			op, err := unmarshalDocumentChangeOperation(raw)
+			if err != nil {
+				return err
+			}
+			w.DocumentChanges = append(w.DocumentChanges, op)
+		}
+	}
+
+	return nil
+}
+
 // WorkspaceFolder - A workspace folder inside a client.
 //
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workspaceFolder
@@ -0,0 +1,154 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// MethodWorkspaceSymbol method name of "workspace/symbol".
+	MethodWorkspaceSymbol = "workspace/symbol"
+
+	// MethodWorkspaceSymbolResolve method name of "workspaceSymbol/resolve".
+	//
+	// @since 3.17.0
+	MethodWorkspaceSymbolResolve = "workspaceSymbol/resolve"
+)
+
+// WorkspaceSymbolParams defines the parameters for a workspace/symbol request.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workspaceSymbolParams
+type WorkspaceSymbolParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+
+	// A query string to filter symbols by. Clients may send an empty
+	// string here to request all symbols.
+	Query string `json:"query"`
+}
+
+// WorkspaceSymbol - A special workspace symbol that supports locations
+// without a range.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workspaceSymbol
+//
+// @since 3.17.0
+type WorkspaceSymbol struct {
+	// The name of this symbol.
+	Name string `json:"name"`
+
+	// The kind of this symbol.
+	Kind SymbolKind `json:"kind"`
+
+	// Tags for this symbol.
+	Tags []SymbolTag `json:"tags,omitempty"`
+
+	// The name of the symbol containing this symbol. This information is for
+	// user interface purposes (e.g. to render a qualifier in the user interface
+	// if necessary). It can't be used to re-infer a hierarchy for the document
+	// symbols.
+	ContainerName string `json:"containerName,omitempty"`
+
+	// The location of this symbol. Whether a server is allowed to
+	// return a location without a range depends on the client
+	// capability `workspace.symbol.resolveSupport`.
+	Location WorkspaceSymbolLocation `json:"location"`
+
+	// A data entry field that is preserved on a workspace symbol between a
+	// workspace symbol request and a workspace symbol resolve request.
+	Data LSPAny `json:"data,omitempty"`
+}
+
+// WorkspaceSymbolLocation is the `Location | { uri: DocumentURI }` location
+// of a WorkspaceSymbol.
+//
+// Location is set for a full location. Otherwise only URI is set and the
+// range can be resolved with a workspaceSymbol/resolve request.
+type WorkspaceSymbolLocation struct {
+	Location *Location
+	URI      DocumentURI
+}
+
+func (l WorkspaceSymbolLocation) MarshalJSON() ([]byte, error) {
+	if l.Location != nil {
+		return json.Marshal(l.Location)
+	}
+	return json.Marshal(struct {
+		URI DocumentURI `json:"uri"`
+	}{l.URI})
+}
+
+func (l *WorkspaceSymbolLocation) UnmarshalJSON(data []byte) error {
+	*l = WorkspaceSymbolLocation{}
+
+	var temp struct {
+		URI   DocumentURI `json:"uri"`
+		Range *Range      `json:"range"`
+	}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	if temp.Range != nil {
+		l.Location = &Location{URI: temp.URI, Range: *temp.Range}
+	}
+	l.URI = temp.URI
+	return nil
+}
+
+// WorkspaceSymbolResponse represents the result of a workspace/symbol request.
+//
+// It can be a slice of SymbolInformation, a slice of WorkspaceSymbols or null.
+// As both look alike on the wire, a result is decoded as SymbolInformation
+// only if it uses the `deprecated` property; otherwise it is decoded as
+// WorkspaceSymbols, which hold every other SymbolInformation property.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#workspace_symbol
+type WorkspaceSymbolResponse struct {
+	SymbolInformation []SymbolInformation
+	WorkspaceSymbols  []WorkspaceSymbol
+	Null              bool
+}
+
+func (r WorkspaceSymbolResponse) MarshalJSON() ([]byte, error) {
+	if r.WorkspaceSymbols != nil {
+		return json.Marshal(r.WorkspaceSymbols)
+	}
+	if r.SymbolInformation != nil {
+		return json.Marshal(r.SymbolInformation)
+	}
+	return []byte("null"), nil
+}
+
+func (r *WorkspaceSymbolResponse) UnmarshalJSON(data []byte) error {
+	// Make sure object is reset.
+	*r = WorkspaceSymbolResponse{}
+
+	if string(data) == "null" {
+		r.Null = true
+		return nil
+	}
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return errors.New("invalid workspace symbol response: not null, []SymbolInformation or []WorkspaceSymbol")
+	}
+
+	for _, item := range items {
+		if _, ok := item["deprecated"]; ok {
+			var infos []SymbolInformation
+			if err := json.Unmarshal(data, &infos); err != nil {
+				return err
+			}
+			r.SymbolInformation = infos
+			return nil
+		}
+	}
+
+	var symbols []WorkspaceSymbol
+	if err := json.Unmarshal(data, &symbols); err != nil {
+		return err
+	}
+	r.WorkspaceSymbols = symbols
+	return nil
+}
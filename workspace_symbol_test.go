@@ -0,0 +1,92 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/laravel-ls/protocol"
+)
+
+func Test_WorkspaceSymbol_ParamsUnmarshalValidJSON(t *testing.T) {
+	var params protocol.WorkspaceSymbolParams
+	if err := json.Unmarshal([]byte(`{"query":"UserController","workDoneToken":"ws"}`), &params); err != nil {
+		t.Fatalf("unmarshal WorkspaceSymbolParams failed: %v", err)
+	}
+	if params.Query != "UserController" || params.WorkDoneToken == nil {
+		t.Fatalf("unexpected WorkspaceSymbolParams: %+v", params)
+	}
+}
+
+func Test_WorkspaceSymbol_LocationVariants(t *testing.T) {
+	full := `{"uri":"file:///app/Http/Controllers/UserController.php","range":{"start":{"line":9,"character":0},"end":{"line":40,"character":1}}}`
+	uriOnly := `{"uri":"file:///resources/views/users/index.blade.php"}`
+
+	var location protocol.WorkspaceSymbolLocation
+	if err := json.Unmarshal([]byte(full), &location); err != nil {
+		t.Fatalf("unmarshal full location failed: %v", err)
+	}
+	if location.Location == nil || location.Location.Range.End.Line != 40 || location.URI != "file:///app/Http/Controllers/UserController.php" {
+		t.Fatalf("expected full location, got %+v", location)
+	}
+	if data, err := json.Marshal(location); err != nil || string(data) != full {
+		t.Fatalf("expected round trip %s, got %s (%v)", full, data, err)
+	}
+
+	if err := json.Unmarshal([]byte(uriOnly), &location); err != nil {
+		t.Fatalf("unmarshal uri-only location failed: %v", err)
+	}
+	if location.Location != nil || location.URI != "file:///resources/views/users/index.blade.php" {
+		t.Fatalf("expected uri-only location, got %+v", location)
+	}
+	if data, err := json.Marshal(location); err != nil || string(data) != uriOnly {
+		t.Fatalf("expected round trip %s, got %s (%v)", uriOnly, data, err)
+	}
+}
+
+func Test_WorkspaceSymbol_ResponseUnmarshal(t *testing.T) {
+	var workspaceSymbols protocol.WorkspaceSymbolResponse
+	data := []byte(`[
+		{"name": "users.index", "kind": 12, "location": {"uri": "file:///routes/web.php"}, "data": {"route": "users.index"}},
+		{"name": "UserController", "kind": 5, "location": {"uri": "file:///app/Http/Controllers/UserController.php", "range": {"start": {"line": 9, "character": 0}, "end": {"line": 40, "character": 1}}}}
+	]`)
+	if err := json.Unmarshal(data, &workspaceSymbols); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if len(workspaceSymbols.WorkspaceSymbols) != 2 || workspaceSymbols.SymbolInformation != nil {
+		t.Fatalf("expected workspace symbols, got %+v", workspaceSymbols)
+	}
+	if workspaceSymbols.WorkspaceSymbols[0].Location.Location != nil || workspaceSymbols.WorkspaceSymbols[0].Data == nil {
+		t.Fatalf("unexpected first symbol: %+v", workspaceSymbols.WorkspaceSymbols[0])
+	}
+	if workspaceSymbols.WorkspaceSymbols[1].Location.Location == nil {
+		t.Fatalf("expected full location for second symbol")
+	}
+
+	var symbolInformation protocol.WorkspaceSymbolResponse
+	data = []byte(`[{"name": "User", "kind": 5, "deprecated": false, "location": {"uri": "file:///app/Models/User.php", "range": {"start": {"line": 0, "character": 0}, "end": {"line": 1, "character": 0}}}}]`)
+	if err := json.Unmarshal(data, &symbolInformation); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if len(symbolInformation.SymbolInformation) != 1 || symbolInformation.WorkspaceSymbols != nil {
+		t.Fatalf("expected symbol information, got %+v", symbolInformation)
+	}
+
+	var null protocol.WorkspaceSymbolResponse
+	if err := json.Unmarshal([]byte(`null`), &null); err != nil || !null.Null {
+		t.Fatalf("expected null response, got %+v, %v", null, err)
+	}
+}
+
+func Test_WorkspaceSymbol_ResolveSupport(t *testing.T) {
+	support := newClientSupport(t, `{"workspace":{"symbol":{"dynamicRegistration":true,"resolveSupport":{"properties":["location.range"]}}}}`)
+
+	if !support.WorkspaceSymbolResolve("location.range") {
+		t.Fatalf("expected location.range to be resolvable")
+	}
+	if support.WorkspaceSymbolResolve("containerName") {
+		t.Fatalf("expected containerName not to be resolvable")
+	}
+	if !support.DynamicRegistration(protocol.MethodWorkspaceSymbol) {
+		t.Fatalf("expected dynamic registration for workspace/symbol")
+	}
+}
@@ -27,3 +27,109 @@ func Test_Workspace_StructsUnmarshalValidJSON(t *testing.T) {
 		t.Fatalf("unexpected WorkspaceFolder: %+v", folder)
 	}
 }
+
+func Test_Workspace_DocumentChangesUnmarshalMixedOperations(t *testing.T) {
+	var ws protocol.WorkspaceEdit
+	if err := json.Unmarshal([]byte(`{
+		"documentChanges":[
+			{"textDocument":{"uri":"file:///tmp/main.go","version":3},"edits":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"newText":"x"}]},
+			{"kind":"create","uri":"file:///tmp/new.go","options":{"overwrite":true}},
+			{"kind":"rename","oldUri":"file:///tmp/a.go","newUri":"file:///tmp/b.go"},
+			{"kind":"delete","uri":"file:///tmp/old.go","annotationId":"a1"}
+		]
+	}`), &ws); err != nil {
+		t.Fatalf("unmarshal WorkspaceEdit failed: %v", err)
+	}
+
+	if len(ws.DocumentChanges) != 4 {
+		t.Fatalf("expected 4 document changes, got %d", len(ws.DocumentChanges))
+	}
+
+	edit, ok := ws.DocumentChanges[0].(protocol.TextDocumentEdit)
+	if !ok || edit.TextDocument.Version != 3 || len(edit.Edits) != 1 {
+		t.Fatalf("unexpected TextDocumentEdit: %#v", ws.DocumentChanges[0])
+	}
+
+	create, ok := ws.DocumentChanges[1].(protocol.CreateFile)
+	if !ok || create.URI != "file:///tmp/new.go" || create.Options == nil || !create.Options.Overwrite {
+		t.Fatalf("unexpected CreateFile: %#v", ws.DocumentChanges[1])
+	}
+
+	rename, ok := ws.DocumentChanges[2].(protocol.RenameFile)
+	if !ok || rename.NewURI != "file:///tmp/b.go" {
+		t.Fatalf("unexpected RenameFile: %#v", ws.DocumentChanges[2])
+	}
+
+	del, ok := ws.DocumentChanges[3].(protocol.DeleteFile)
+	if !ok || del.URI != "file:///tmp/old.go" || del.AnnotationID != "a1" {
+		t.Fatalf("unexpected DeleteFile: %#v", ws.DocumentChanges[3])
+	}
+}
+
+func Test_Workspace_DocumentChangesRoundTrip(t *testing.T) {
+	original := protocol.WorkspaceEdit{
+		DocumentChanges: []protocol.DocumentChangeOperation{
+			protocol.TextDocumentEdit{
+				TextDocument: protocol.VersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: "file:///tmp/main.go"},
+					Version:                1,
+				},
+				Edits: []protocol.TextEdit{{NewText: "package main"}},
+			},
+			protocol.CreateFile{URI: "file:///tmp/new.go"},
+			protocol.RenameFile{OldURI: "file:///tmp/a.go", NewURI: "file:///tmp/b.go"},
+			protocol.DeleteFile{URI: "file:///tmp/old.go"},
+		},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal WorkspaceEdit failed: %v", err)
+	}
+
+	var decoded protocol.WorkspaceEdit
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal WorkspaceEdit failed: %v", err)
+	}
+
+	if len(decoded.DocumentChanges) != len(original.DocumentChanges) {
+		t.Fatalf("expected %d document changes, got %d", len(original.DocumentChanges), len(decoded.DocumentChanges))
+	}
+
+	if _, ok := decoded.DocumentChanges[0].(protocol.TextDocumentEdit); !ok {
+		t.Fatalf("expected TextDocumentEdit, got %#v", decoded.DocumentChanges[0])
+	}
+
+	if create, ok := decoded.DocumentChanges[1].(protocol.CreateFile); !ok || create.Kind != "create" {
+		t.Fatalf("expected CreateFile with kind create, got %#v", decoded.DocumentChanges[1])
+	}
+
+	if rename, ok := decoded.DocumentChanges[2].(protocol.RenameFile); !ok || rename.Kind != "rename" {
+		t.Fatalf("expected RenameFile with kind rename, got %#v", decoded.DocumentChanges[2])
+	}
+
+	if del, ok := decoded.DocumentChanges[3].(protocol.DeleteFile); !ok || del.Kind != "delete" {
+		t.Fatalf("expected DeleteFile with kind delete, got %#v", decoded.DocumentChanges[3])
+	}
+}
+
+func Test_Workspace_DocumentChangesUnknownKind(t *testing.T) {
+	var ws protocol.WorkspaceEdit
+	if err := json.Unmarshal([]byte(`{"documentChanges":[{"kind":"move","uri":"file:///tmp/a.go"}]}`), &ws); err == nil {
+		t.Fatalf("expected error for unknown document change kind")
+	}
+}
+
+func Test_Workspace_DocumentChangesRejectMalformedEntries(t *testing.T) {
+	for _, changes := range []string{
+		`[null]`,
+		`[{}]`,
+		`[{"edits":[]}]`,
+		`[{"textDocument":null,"edits":[]}]`,
+	} {
+		var ws protocol.WorkspaceEdit
+		if err := json.Unmarshal([]byte(`{"documentChanges":`+changes+`}`), &ws); err == nil {
+			t.Fatalf("expected error for document changes %s, got %+v", changes, ws.DocumentChanges)
+		}
+	}
+}